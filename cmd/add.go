@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <addon>[,<addon>...] [path]",
+	Short: "Add add-ons to a project cc has initialized",
+	Long: `Add layers add-ons onto a project set up with cc init or cc new. The project
+is generated again with the options recorded in .claude/.cc-manifest.json plus
+the new add-ons: their files are created, the cc managed blocks in the Makefile
+and .gitignore are updated, and generated files such as CLAUDE.md and the CI
+workflow are brought up to date unless you have modified them.
+
+Add-ons that cannot be combined, with each other or with the project type,
+are rejected before anything is written.
+
+Add-ons:
+` + addOnList(),
+	Example: `  cc add helm                                # Add a Helm chart
+  cc add docker,postgres ../api              # Several add-ons, another directory
+  cc add terraform --dry-run                 # Show what would change`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.RangeArgs(1, 2)(cmd, args); err != nil {
+			return usageError(err)
+		}
+		return nil
+	},
+	RunE: runAdd,
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+}
+
+// addOnList describes the built-in add-ons for help output.
+func addOnList() string {
+	var b strings.Builder
+	for _, a := range cc.AddOns() {
+		fmt.Fprintf(&b, "  %-16s %s\n", a.Name, a.Description)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// parseAddOns splits comma separated add-on names and checks that they exist.
+func parseAddOns(values []string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, a := range cc.AddOns() {
+		known[a.Name] = true
+	}
+
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !known[name] {
+				return nil, usageError(fmt.Errorf("unknown add-on %q (see cc add --help)", name))
+			}
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func runAdd(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("add")
+
+	return emit(printer, report, addAddOns(printer, report, args))
+}
+
+func addAddOns(printer *output.Printer, report *output.Report, args []string) error {
+	names, err := parseAddOns(args[:1])
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return usageError(fmt.Errorf("no add-ons given"))
+	}
+
+	dir, err := projectDir(args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	plan, err := engine.PlanAddOns(ctx, dir, names)
+	if err != nil {
+		return fmt.Errorf("failed to plan add-ons: %w", err)
+	}
+
+	var skipped []string
+	for _, file := range plan.Files {
+		report.AddFile(file.Path, file.Action)
+		if file.Action == cc.ActionSkipped {
+			skipped = append(skipped, file.Path)
+		}
+	}
+
+	if len(skipped) > 0 {
+		report.Warn("Kept files modified since cc generated them: %v", skipped)
+		report.Warn("Add the add-ons' changes to them by hand, or rerun with cc init --overwrite")
+	}
+
+	if viper.GetBool("dry-run") {
+		printer.Verbose = true
+		report.Summary = "DRY RUN - No files will be created"
+		return nil
+	}
+
+	if err := engine.Apply(ctx, plan); err != nil {
+		return fmt.Errorf("failed to add %s: %w", strings.Join(names, ", "), err)
+	}
+
+	report.Summary = fmt.Sprintf("✅ Added %s to %s", strings.Join(names, ", "), plan.Project.Name)
+	report.NextSteps = append(report.NextSteps,
+		"Review the new sections in CLAUDE.md",
+		"Run 'make help' to see the new targets",
+		"Revert with 'cc undo' if needed",
+	)
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var ciCmd = &cobra.Command{
+	Use:   "ci",
+	Short: "Maintain GitHub Actions workflows",
+}
+
+var ciPinCmd = &cobra.Command{
+	Use:   "pin [path]",
+	Short: "Pin actions in existing workflows to commit SHAs",
+	Long: `Pin rewrites every uses: reference in .github/workflows to the full commit
+SHA of the release in cc's version table, keeping the version as a comment
+(actions/checkout@<sha> # v4.2.2).
+
+References that are already SHAs are left alone, as are tags of a different
+major version than the one cc knows, so newer releases are never downgraded.
+Actions cc has no version for are listed as warnings.
+
+The change is recorded like any other run and can be reverted with cc undo.`,
+	Example: `  cc ci pin                                  # Pin workflows in the current project
+  cc ci pin --dry-run                        # Show which workflows would change`,
+	Args: projectArgs,
+	RunE: runCIPin,
+}
+
+func init() {
+	rootCmd.AddCommand(ciCmd)
+	ciCmd.AddCommand(ciPinCmd)
+}
+
+func runCIPin(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("ci pin")
+
+	return emit(printer, report, pinActions(printer, report, args))
+}
+
+func pinActions(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	dryRun := viper.GetBool("dry-run")
+
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	result, err := engine.PinActions(context.Background(), dir, cc.PinOptions{DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to pin actions: %w", err)
+	}
+
+	for _, path := range result.Files {
+		report.AddFile(path, "pinned")
+	}
+	for _, unpinned := range result.Unpinned {
+		report.Warn("Not pinned: %s", unpinned)
+	}
+
+	printer.Verbose = true
+
+	if dryRun {
+		report.Summary = fmt.Sprintf("DRY RUN - Would pin %d action references in %d workflows", result.Pinned, len(result.Files))
+	} else {
+		report.Summary = fmt.Sprintf("✅ Pinned %d action references in %d workflows", result.Pinned, len(result.Files))
+	}
+
+	return nil
+}
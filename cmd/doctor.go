@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [path]",
+	Short: "Check the project and environment for Claude Code readiness",
+	Long: `Doctor runs a set of checks against the current project and the local
+environment and reports each one as ok, warn or fail.
+
+Doctor exits with code 3 if any check fails.`,
+	Example: `  cc doctor                                  # Run all checks
+  cc doctor --output=json                    # Machine-readable results`,
+	Args: projectArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("doctor")
+
+	return emit(printer, report, doctor(printer, report, args))
+}
+
+func doctor(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	report.Checks = []output.Check{
+		checkGitRepository(dir),
+		checkClaudeMD(dir),
+		checkManifest(dir),
+		checkBackupsIgnored(dir),
+		checkBinary("claude", output.CheckWarn, "install Claude Code to use the generated configuration"),
+		checkBinary("make", output.CheckWarn, "the generated Makefile needs make"),
+	}
+
+	failed := 0
+	for _, check := range report.Checks {
+		if !printer.Structured() {
+			printer.Printf("  %-5s %-16s %s\n", check.Status, check.Name, check.Message)
+		}
+		if check.Status == output.CheckFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return checkFailed(fmt.Errorf("%d doctor checks failed", failed))
+	}
+
+	report.Summary = "✅ All checks passed"
+	return nil
+}
+
+func checkGitRepository(root string) output.Check {
+	check := output.Check{Name: "git-repository", Status: output.CheckOK, Message: "project is a git repository"}
+	if _, err := os.Stat(filepath.Join(root, ".git")); err != nil {
+		check.Status = output.CheckWarn
+		check.Message = "project is not a git repository"
+	}
+	return check
+}
+
+func checkClaudeMD(root string) output.Check {
+	check := output.Check{Name: "claude-md", Status: output.CheckOK, Message: "CLAUDE.md found"}
+	if _, err := os.Stat(filepath.Join(root, "CLAUDE.md")); err != nil {
+		check.Status = output.CheckWarn
+		check.Message = "CLAUDE.md not found, run 'cc init'"
+	}
+	return check
+}
+
+func checkManifest(root string) output.Check {
+	check := output.Check{Name: "manifest", Status: output.CheckOK}
+
+	statuses, err := cc.New().Status(context.Background(), root)
+	if err != nil {
+		check.Status = output.CheckFail
+		check.Message = err.Error()
+		return check
+	}
+
+	missing := 0
+	for _, status := range statuses {
+		if status.State == cc.StateMissing {
+			missing++
+		}
+	}
+
+	check.Message = fmt.Sprintf("%d generated files tracked", len(statuses))
+	if missing > 0 {
+		check.Status = output.CheckWarn
+		check.Message = fmt.Sprintf("%d of %d generated files are missing", missing, len(statuses))
+	}
+	return check
+}
+
+func checkBackupsIgnored(root string) output.Check {
+	check := output.Check{Name: "backups-ignored", Status: output.CheckOK, Message: "backups are git-ignored"}
+
+	if _, err := os.Stat(filepath.Join(root, cc.BackupDir)); err != nil {
+		check.Message = "no backups present"
+		return check
+	}
+	if _, err := os.Stat(filepath.Join(root, cc.BackupDir, ".gitignore")); err != nil {
+		check.Status = output.CheckFail
+		check.Message = fmt.Sprintf("%s is not git-ignored", cc.BackupDir)
+	}
+	return check
+}
+
+func checkBinary(name, status, hint string) output.Check {
+	check := output.Check{Name: name, Status: output.CheckOK}
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		check.Status = status
+		check.Message = fmt.Sprintf("%s not found on PATH: %s", name, hint)
+		return check
+	}
+
+	check.Message = path
+	return check
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Work with the environment variables the project reads",
+	Long: `Env works with the environment variables read by the project's code: Go's
+os.Getenv and os.LookupEnv, Python's os.environ and os.getenv, Node's
+process.env, the fields of pydantic BaseSettings classes and Terraform
+variables, set as TF_VAR_<name>. Tests and build output are left out.
+
+cc init writes them to ` + cc.EnvExampleFile + ` and lists them in CLAUDE.md.`,
+}
+
+var envCheckCmd = &cobra.Command{
+	Use:   "check [path]",
+	Short: "Report environment variables missing from " + cc.EnvExampleFile,
+	Long: `Check reports every environment variable the code reads that ` + cc.EnvExampleFile + `
+does not set or document in a comment, and warns about variables in
+` + cc.EnvExampleFile + ` that the code does not read.
+
+Check exits with code 3 if any variable is missing.`,
+	Example: `  cc env check                               # Check the current project
+  cc env check --output=json                 # Machine-readable findings`,
+	Args: projectArgs,
+	RunE: runEnvCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envCheckCmd)
+}
+
+func runEnvCheck(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("env check")
+
+	return emit(printer, report, envCheck(printer, report, args))
+}
+
+func envCheck(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	engine := cc.New(cc.WithLog(printer.Log()))
+	result, err := engine.CheckEnv(context.Background(), dir)
+	if err != nil {
+		return fmt.Errorf("failed to check environment variables: %w", err)
+	}
+
+	for _, v := range result.Missing {
+		report.Findings = append(report.Findings, output.Finding{
+			Path:    v.Uses[0].Path,
+			Line:    v.Uses[0].Line,
+			Rule:    "env-missing",
+			Message: v.Name + " is read here but is not in " + cc.EnvExampleFile,
+		})
+	}
+	for _, name := range result.Unused {
+		report.Warn("%s is in %s but the code does not read it", name, cc.EnvExampleFile)
+	}
+
+	if len(report.Findings) > 0 {
+		if !printer.Structured() {
+			for _, finding := range report.Findings {
+				printer.Printf("  %s\n", finding)
+			}
+			for _, warning := range report.Warnings {
+				printer.Printf("⚠️  %s\n", warning)
+			}
+		}
+		if result.Example {
+			printer.Printf("Add the missing variables to %s\n", cc.EnvExampleFile)
+		} else {
+			printer.Printf("Run cc init to generate %s\n", cc.EnvExampleFile)
+		}
+		return checkFailed(fmt.Errorf("%d environment variables missing from %s", len(report.Findings), cc.EnvExampleFile))
+	}
+
+	report.Summary = "✅ " + cc.EnvExampleFile + " documents every environment variable the code reads"
+	return nil
+}
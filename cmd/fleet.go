@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/onprema/cc/internal/diff"
+	"github.com/onprema/cc/internal/gitutil"
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Apply cc across many repositories",
+	Long:  `Fleet runs cc against many local repository checkouts at once.`,
+}
+
+var fleetApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Initialize Claude Code optimization in many repositories",
+	Long: `Apply plans and applies Claude Code optimization in every repository
+listed in --repos or matched by --glob, using a bounded pool of workers.
+
+The repos file has one path per line; blank lines and lines starting with #
+are ignored, and relative paths are relative to the current directory. Each
+repository is named after its directory.
+
+With --git-branch and/or --git-commit, every repository must have a clean
+working tree. cc creates the branch, applies its changes and commits only the
+files it touched. Nothing is pushed.
+
+Each repository is reported as created (files were added or updated),
+skipped (nothing to do), conflicted (some existing files differ from what cc
+would write and were left alone; use --overwrite to replace them) or failed.`,
+	Example: `  cc fleet apply --repos repos.txt --dry-run          # Show diffs for every repo
+  cc fleet apply --glob 'services/*' --github         # Apply to matching checkouts
+  cc fleet apply --repos repos.txt --git-branch=chore/claude-code --git-commit`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.NoArgs(cmd, args); err != nil {
+			return usageError(err)
+		}
+		return nil
+	},
+	RunE: runFleetApply,
+}
+
+var (
+	fleetRepos       string
+	fleetGlobs       []string
+	fleetConcurrency int
+	fleetDescription string
+	fleetGitHub      string
+	fleetOverwrite   bool
+	fleetBranch      string
+	fleetCommit      bool
+)
+
+// Repository statuses in a fleet report.
+const (
+	repoCreated    = "created"
+	repoSkipped    = "skipped"
+	repoConflicted = "conflicted"
+	repoFailed     = "failed"
+)
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+	fleetCmd.AddCommand(fleetApplyCmd)
+
+	fleetApplyCmd.Flags().StringVar(&fleetRepos, "repos", "", "File listing repository paths, one per line")
+	fleetApplyCmd.Flags().StringArrayVar(&fleetGlobs, "glob", nil, "Glob matching repository directories (repeatable)")
+	fleetApplyCmd.Flags().IntVarP(&fleetConcurrency, "concurrency", "j", runtime.NumCPU(), "Number of repositories processed at once")
+	fleetApplyCmd.Flags().StringVarP(&fleetDescription, "description", "d", "", "Project description")
+	githubFlag(fleetApplyCmd, &fleetGitHub)
+	fleetApplyCmd.Flags().BoolVarP(&fleetOverwrite, "overwrite", "o", false, "Overwrite existing files")
+	fleetApplyCmd.Flags().StringVar(&fleetBranch, "git-branch", "", "Create and check out this branch in each repository before applying")
+	fleetApplyCmd.Flags().BoolVar(&fleetCommit, "git-commit", false, "Commit the generated files in each repository")
+}
+
+func runFleetApply(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("fleet apply")
+
+	return emit(printer, report, fleetApply(printer, report))
+}
+
+func fleetApply(printer *output.Printer, report *output.Report) error {
+	repos, err := fleetRepositories()
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return usageError(errors.New("no repositories given: use --repos or --glob"))
+	}
+	if fleetConcurrency < 1 {
+		return usageError(fmt.Errorf("--concurrency must be at least 1"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	dryRun := viper.GetBool("dry-run")
+	results := make([]fleetResult, len(repos))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(fleetConcurrency, len(repos)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = applyRepo(ctx, repos[i], dryRun)
+				if printer.Verbose {
+					printer.Printf("%s: %s\n", repos[i], results[i].repo.Status)
+				}
+			}
+		}()
+	}
+	for i := range repos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		report.Repos = append(report.Repos, result.repo)
+		report.Files = append(report.Files, result.files...)
+		counts[result.repo.Status]++
+	}
+
+	// A dry run exists to show the diffs
+	if dryRun {
+		printer.Verbose = true
+	}
+
+	report.Summary = fmt.Sprintf("%d repositories: %d created, %d skipped, %d conflicted, %d failed",
+		len(repos), counts[repoCreated], counts[repoSkipped], counts[repoConflicted], counts[repoFailed])
+	if dryRun {
+		report.Summary = "DRY RUN - " + report.Summary
+	}
+	if counts[repoConflicted] > 0 {
+		report.NextSteps = append(report.NextSteps, "Review conflicted repositories and rerun with --overwrite to replace differing files")
+	}
+
+	if counts[repoFailed] > 0 {
+		// Still print the per-repository table, which says what failed
+		if !printer.Structured() {
+			printer.Emit(report)
+		}
+		return fmt.Errorf("%d of %d repositories failed", counts[repoFailed], len(repos))
+	}
+
+	return nil
+}
+
+// fleetRepositories collects the repository directories from --repos and
+// --glob, without duplicates and in the order given.
+func fleetRepositories() ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			repos = append(repos, path)
+		}
+	}
+
+	if fleetRepos != "" {
+		file, err := os.Open(fleetRepos)
+		if err != nil {
+			return nil, usageError(fmt.Errorf("failed to read repos file: %w", err))
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			add(line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read repos file: %w", err)
+		}
+	}
+
+	for _, pattern := range fleetGlobs {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, usageError(fmt.Errorf("invalid glob %q: %w", pattern, err))
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && info.IsDir() {
+				add(match)
+			}
+		}
+	}
+
+	return repos, nil
+}
+
+type fleetResult struct {
+	repo  output.Repo
+	files []output.FileAction
+}
+
+// applyRepo runs the plan/apply pipeline, with optional git steps, in a single
+// repository. Failures are reported in the result rather than returned, so one
+// broken repository does not stop the rest.
+func applyRepo(ctx context.Context, dir string, dryRun bool) fleetResult {
+	result := fleetResult{repo: output.Repo{Path: dir}}
+	fail := func(err error) fleetResult {
+		result.repo.Status = repoFailed
+		result.repo.Error = err.Error()
+		return result
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fail(err)
+	}
+	if !info.IsDir() {
+		return fail(fmt.Errorf("not a directory"))
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fail(err)
+	}
+
+	description := fleetDescription
+	if description == "" {
+		description = "A project optimized for Claude Code development"
+	}
+
+	engine := cc.New()
+	plan, err := engine.Plan(ctx, cc.Options{
+		Dir:            dir,
+		Name:           filepath.Base(abs),
+		Description:    description,
+		GitHub:         fleetGitHub != "",
+		GitHubUsername: githubOwner(fleetGitHub),
+		Overwrite:      fleetOverwrite,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, file := range plan.Files {
+		action := output.FileAction{Path: filepath.ToSlash(filepath.Join(dir, file.Path)), Action: file.Action}
+
+		switch file.Action {
+		case cc.ActionCreated, cc.ActionReplaced, cc.ActionMerged:
+			result.repo.Created++
+			if dryRun {
+				action.Diff = planDiff(dir, file)
+			}
+		case cc.ActionUnchanged:
+			result.repo.Skipped++
+		case cc.ActionSkipped:
+			result.repo.Conflicted++
+		}
+
+		result.files = append(result.files, action)
+	}
+
+	switch {
+	case result.repo.Conflicted > 0:
+		result.repo.Status = repoConflicted
+	case result.repo.Created > 0:
+		result.repo.Status = repoCreated
+	default:
+		result.repo.Status = repoSkipped
+	}
+
+	if result.repo.Created == 0 {
+		return result
+	}
+
+	// Check git before a dry run returns, so the preview fails where the real
+	// run would
+	var repo *gitutil.Repo
+	if fleetBranch != "" || fleetCommit {
+		if repo, err = gitutil.Open(dir); err != nil {
+			return fail(err)
+		}
+		if err := checkClean(repo); err != nil {
+			return fail(err)
+		}
+	}
+
+	if dryRun {
+		return result
+	}
+
+	if fleetBranch != "" {
+		if err := repo.CreateBranch(fleetBranch); err != nil {
+			return fail(err)
+		}
+		result.repo.Branch = fleetBranch
+	}
+
+	if err := engine.Apply(ctx, plan); err != nil {
+		return fail(err)
+	}
+
+	if fleetCommit {
+		commit, err := commitPlan(repo, plan)
+		if err != nil {
+			// Leave the repository as it was rather than half committed
+			if _, undoErr := engine.Undo(ctx, dir); undoErr != nil {
+				err = fmt.Errorf("%w (undo failed: %v)", err, undoErr)
+			}
+			return fail(err)
+		}
+		result.repo.Commit = commit
+		if result.repo.Branch == "" {
+			result.repo.Branch, _ = repo.CurrentBranch()
+		}
+	}
+
+	return result
+}
+
+// planDiff renders the change a planned file would make.
+func planDiff(dir string, file cc.FileChange) string {
+	fromName := "a/" + file.Path
+	current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		fromName = ""
+	}
+
+	return diff.Unified(fromName, "b/"+file.Path, string(current), string(file.Content))
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onprema/cc/internal/output"
+)
+
+func TestFleetApply(t *testing.T) {
+	fresh := newGitRepo(t, map[string]string{"README.md": "# Fresh\n"})
+	conflicted := newGitRepo(t, map[string]string{"CLAUDE.md": "# Our own notes\n"})
+	done := newGitRepo(t, map[string]string{"README.md": "# Done\n"})
+	if _, err := execute(t, "init", "--git-commit", done); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	repos := filepath.Join(t.TempDir(), "repos.txt")
+	writeFiles(t, filepath.Dir(repos), map[string]string{
+		"repos.txt": "# services\n" + fresh + "\n\n" + conflicted + "\n" + done + "\n" + missing + "\n" + fresh + "\n",
+	})
+
+	report, err := execute(t, "fleet", "apply", "--repos", repos, "--concurrency=2", "--git-branch=chore/claude-code", "--git-commit")
+	if ExitCode(err) != ExitError {
+		t.Fatalf("err = %v, want a failure for the missing repository", err)
+	}
+	if report == nil || report.Status != output.StatusError {
+		t.Fatalf("report = %+v", report)
+	}
+
+	// Repositories are reported once each, in the order given
+	statuses := make(map[string]output.Repo)
+	var order []string
+	for _, repo := range report.Repos {
+		statuses[repo.Path] = repo
+		order = append(order, repo.Path)
+	}
+	if want := []string{fresh, conflicted, done, missing}; strings.Join(order, "\n") != strings.Join(want, "\n") {
+		t.Errorf("repos = %q, want %q", order, want)
+	}
+	for path, want := range map[string]string{fresh: repoCreated, conflicted: repoConflicted, done: repoSkipped, missing: repoFailed} {
+		if got := statuses[path].Status; got != want {
+			t.Errorf("%s: status = %q (%s), want %q", filepath.Base(path), got, statuses[path].Error, want)
+		}
+	}
+
+	if repo := statuses[fresh]; repo.Branch != "chore/claude-code" || repo.Commit == "" {
+		t.Errorf("fresh repository: %+v", repo)
+	}
+	if branch := git(t, fresh, "rev-parse", "--abbrev-ref", "HEAD"); branch != "chore/claude-code" {
+		t.Errorf("fresh repository branch = %q", branch)
+	}
+	if status := git(t, fresh, "status", "--porcelain"); status != "" {
+		t.Errorf("fresh repository not clean:\n%s", status)
+	}
+
+	// Conflicting files are left alone
+	if data, err := os.ReadFile(filepath.Join(conflicted, "CLAUDE.md")); err != nil || string(data) != "# Our own notes\n" {
+		t.Errorf("conflicted CLAUDE.md = %q, %v", data, err)
+	}
+	if branch := git(t, done, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("skipped repository branch = %q, want main", branch)
+	}
+}
+
+func TestFleetApplyDryRun(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"services/api/README.md": "# API\n", "services/web/README.md": "# Web\n", "services/notes.txt": "x\n"})
+
+	report, err := execute(t, "fleet", "apply", "--dry-run", "--glob", filepath.Join(root, "services", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Repos) != 2 || !strings.HasPrefix(report.Summary, "DRY RUN - 2 repositories: 2 created") {
+		t.Errorf("repos = %+v, summary = %q", report.Repos, report.Summary)
+	}
+
+	var diffs int
+	for _, file := range report.Files {
+		if file.Diff != "" {
+			diffs++
+		}
+	}
+	if diffs == 0 {
+		t.Error("dry run shows no diffs")
+	}
+	if _, err := os.Stat(filepath.Join(root, "services", "api", "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote CLAUDE.md: %v", err)
+	}
+}
+
+func TestFleetApplyDryRunChecksGit(t *testing.T) {
+	clean := newGitRepo(t, map[string]string{"README.md": "# Clean\n"})
+	dirty := newGitRepo(t, map[string]string{"README.md": "# Dirty\n"})
+	writeFiles(t, dirty, map[string]string{"notes.txt": "mine\n"})
+	plain := t.TempDir()
+
+	repos := filepath.Join(t.TempDir(), "repos.txt")
+	writeFiles(t, filepath.Dir(repos), map[string]string{"repos.txt": clean + "\n" + dirty + "\n" + plain + "\n"})
+
+	report, err := execute(t, "fleet", "apply", "--dry-run", "--repos", repos, "--git-commit")
+	if ExitCode(err) != ExitError {
+		t.Fatalf("err = %v, want a failure for the dirty and plain directories", err)
+	}
+	statuses := make(map[string]output.Repo)
+	for _, repo := range report.Repos {
+		statuses[repo.Path] = repo
+	}
+	for path, want := range map[string]string{clean: repoCreated, dirty: repoFailed, plain: repoFailed} {
+		if got := statuses[path].Status; got != want {
+			t.Errorf("%s: status = %q (%s), want %q", path, got, statuses[path].Error, want)
+		}
+	}
+	if status := git(t, clean, "status", "--porcelain"); status != "" {
+		t.Errorf("dry run changed the clean repository:\n%s", status)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onprema/cc/internal/gitutil"
+	"github.com/onprema/cc/pkg/cc"
+)
+
+// changedFiles returns the paths a plan creates or modifies, plus the manifest
+// that applying it updates. These are the files cc stages for a commit.
+func changedFiles(plan *cc.Plan) []string {
+	var paths []string
+	for _, file := range plan.Files {
+		switch file.Action {
+		case cc.ActionCreated, cc.ActionReplaced, cc.ActionMerged:
+			paths = append(paths, file.Path)
+		}
+	}
+
+	if len(paths) > 0 {
+		paths = append(paths, cc.ManifestFile)
+	}
+
+	return paths
+}
+
+// commitMessage builds a conventional commit message listing the files cc
+// touched.
+func commitMessage(paths []string) string {
+	var b strings.Builder
+
+	b.WriteString("chore: add Claude Code optimization\n\n")
+	b.WriteString("Generated by cc. Files added or updated:\n\n")
+	for _, path := range paths {
+		fmt.Fprintf(&b, "- %s\n", path)
+	}
+
+	return b.String()
+}
+
+// checkClean returns an error if the working tree has uncommitted changes.
+func checkClean(repo *gitutil.Repo) error {
+	changes, err := repo.Changes()
+	if err != nil {
+		return err
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("working tree has %d uncommitted changes", len(changes))
+	}
+	return nil
+}
+
+// commitPlan stages and commits only the files an applied plan touched, and
+// returns the new commit's hash. If the commit fails, the files are unstaged
+// again.
+func commitPlan(repo *gitutil.Repo, plan *cc.Plan) (string, error) {
+	paths := changedFiles(plan)
+	if err := repo.Add(paths...); err != nil {
+		return "", err
+	}
+	commit, err := repo.Commit(commitMessage(paths), paths...)
+	if err != nil {
+		if resetErr := repo.Unstage(paths...); resetErr != nil {
+			err = fmt.Errorf("%w (unstaging also failed: %v)", err, resetErr)
+		}
+		return "", err
+	}
+	return commit, nil
+}
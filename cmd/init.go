@@ -1,106 +1,341 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
-	"github.com/onprema/cc/internal/generator"
+	"github.com/onprema/cc/internal/gitutil"
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var initCmd = &cobra.Command{
-	Use:   "init",
+	Use:   "init [path]",
 	Short: "Initialize Claude Code optimization for current project",
 	Long: `Initialize adds Claude Code optimization to your current project.
 It creates .claude/ directory, CLAUDE.md file, development workflows,
 and other files to make your project work seamlessly with Claude Code.
 
 This command is safe to run multiple times and will not overwrite
-existing files unless --overwrite is specified.`,
+existing files unless --overwrite is specified.
+
+The project directory defaults to the current directory, and the project
+name defaults to the directory's name. GitHub integration is enabled only by
+--github; the git remote never enables it. With --github, the owner, host and
+repository name come from a GitHub remote (github.com or GitHub Enterprise)
+unless --github=OWNER, --github-host or --github-repo say otherwise.
+
+CODEOWNERS rules come from --codeowner PATTERN=OWNER[,OWNER...] flags or the
+codeowners list in the config file; with GitHub integration and no rules, the
+GitHub owner owns everything. Each pattern must match at least one file. Rules
+are merged into an existing CODEOWNERS (.github/, the root, docs/ or .gitlab/)
+without repeating the ones already there.
+
+With --git-branch and/or --git-commit, cc requires a clean working tree
+(unless --allow-dirty is given), creates the branch, and commits only the
+files it touched. Nothing is pushed.`,
 	Example: `  cc init                                    # Basic Claude Code setup
+  cc init --github                          # Add GitHub integration for the remote's owner
   cc init --github=username                 # Add GitHub integration  
   cc init --description="My project"        # Add project description
-  cc init --overwrite                       # Overwrite existing files`,
+  cc init --overwrite                       # Overwrite existing files
+  cc init ../service --name=billing         # Initialize another directory
+  cc init --git-branch=chore/claude-code --git-commit  # Commit on a new branch
+  cc init --release                         # Add changelog and release tooling
+  cc init --codeowner='*=@acme/core' --codeowner='/docs/=@acme/docs'`,
+	Args: projectArgs,
 	RunE: runInit,
 }
 
 var (
-	description string
-	github      string
-	overwrite   bool
+	name           string
+	projectType    string
+	description    string
+	github         string
+	githubHost     string
+	githubRepo     string
+	goModule       string
+	codeOwnerRules []string
+	issueTemplates string
+	claudeActions  bool
+	release        bool
+	helm           bool
+	dagger         bool
+	withAddOns     []string
+	denySecrets    bool
+	overwrite      bool
+	gitBranch      string
+	gitCommit      bool
+	allowDirty     bool
 )
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 
+	initCmd.Flags().StringVarP(&name, "name", "n", "", "Project name (default is the directory name)")
 	initCmd.Flags().StringVarP(&description, "description", "d", "", "Project description")
-	initCmd.Flags().StringVarP(&github, "github", "g", "", "GitHub username for integration")
+	initCmd.Flags().StringVarP(&projectType, "type", "t", "", "Project type (see cc new --help for the list)")
+	githubFlag(initCmd, &github)
+	initCmd.Flags().StringVar(&githubHost, "github-host", "", "GitHub host, for GitHub Enterprise (default from the git remote, or github.com)")
+	initCmd.Flags().StringVar(&githubRepo, "github-repo", "", "GitHub repository name (default from the git remote, or the project name)")
+	initCmd.Flags().StringVar(&goModule, "module", "", "Go module path for the go type (default from the git remote)")
+	initCmd.Flags().StringArrayVar(&codeOwnerRules, "codeowner", nil, "CODEOWNERS rule as PATTERN=OWNER[,OWNER...] (repeatable)")
+	initCmd.Flags().StringVar(&issueTemplates, "issue-templates", cc.IssueTemplatesForms, "GitHub issue templates: forms (YAML issue forms) or markdown")
+	initCmd.Flags().BoolVar(&claudeActions, "claude-actions", false, "Add GitHub workflows for Claude pull request review and @claude mentions")
+	initCmd.Flags().BoolVar(&release, "release", false, "Add changelog and release tooling (GoReleaser for Go), make release and a /release command")
+	initCmd.Flags().StringSliceVar(&withAddOns, "with", nil, "Add-ons to layer onto the project type, e.g. docker,helm (see cc add --help)")
+	initCmd.Flags().BoolVar(&helm, "helm", false, "Add a Helm chart with a values schema (same as --with helm)")
+	initCmd.Flags().BoolVar(&dagger, "dagger", false, "Run CI through a Dagger pipeline in Go (same as --with dagger)")
+	initCmd.Flags().BoolVar(&denySecrets, "deny-secrets", false, "Deny Claude Code reading .env and other credential files in .claude/settings.json")
+	initCmd.Flags().StringArray("ci-matrix", nil, "CI matrix axis as NAME=VALUE[,VALUE...], e.g. os=ubuntu-latest,macos-latest or go=1.23,1.24 (repeatable)")
 	initCmd.Flags().BoolVarP(&overwrite, "overwrite", "o", false, "Overwrite existing files")
+	initCmd.Flags().StringVar(&gitBranch, "git-branch", "", "Create and check out this branch before applying")
+	initCmd.Flags().BoolVar(&gitCommit, "git-commit", false, "Commit the generated files")
+	initCmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "Warn instead of failing when the working tree has uncommitted changes")
+
+	viper.BindPFlag("ci-matrix", initCmd.Flags().Lookup("ci-matrix"))
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	// Get current directory name as project name
-	cwd, err := os.Getwd()
+	printer, err := newPrinter()
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+		return err
+	}
+	report := output.NewReport("init")
+
+	return emit(printer, report, initProject(printer, report, args))
+}
+
+func initProject(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	return generateProject(printer, report, dir)
+}
+
+// generateProject plans and applies the files for the project in dir,
+// configured by the init flags.
+func generateProject(printer *output.Printer, report *output.Report, dir string) error {
+	// Use the directory name as project name unless one is given
+	projectName := name
+	if projectName == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve project directory: %w", err)
+		}
+		projectName = filepath.Base(abs)
 	}
-	
-	projectName := filepath.Base(cwd)
 	
 	// Set default description if not provided
 	if description == "" {
 		description = fmt.Sprintf("A project optimized for Claude Code development")
 	}
 
-	// Check for existing Claude Code files
-	gen := generator.New()
-	existing := gen.CheckExistingClaudeFiles(".")
-	
-	if len(existing) > 0 && !overwrite {
-		fmt.Printf("Found existing Claude Code files: %v\n", existing)
-		fmt.Println("Use --overwrite to replace existing files")
-		fmt.Println("Or run with different flags to add missing files")
+	if projectType != "" && !knownProjectType(projectType) {
+		return usageError(fmt.Errorf("unknown project type %q (see cc new --help)", projectType))
 	}
 
-	config := &generator.ProjectConfig{
+	if issueTemplates != cc.IssueTemplatesForms && issueTemplates != cc.IssueTemplatesMarkdown {
+		return usageError(fmt.Errorf("invalid --issue-templates %q (must be forms or markdown)", issueTemplates))
+	}
+
+	addOns, err := parseAddOns(withAddOns)
+	if err != nil {
+		return err
+	}
+
+	// Rules given as flags replace those in the config file
+	rules := codeOwnerRules
+	if len(rules) == 0 {
+		rules = viper.GetStringSlice("codeowners")
+	}
+	codeOwners, err := parseCodeOwners(rules)
+	if err != nil {
+		return err
+	}
+
+	matrix, err := parseMatrix(viper.GetStringSlice("ci-matrix"))
+	if err != nil {
+		return err
+	}
+
+	opts := cc.Options{
+		Dir:            dir,
 		Name:           projectName,
+		Type:           projectType,
 		Description:    description,
-		GitHubUsername: github,
+		GitHub:         github != "",
+		GitHubUsername: githubOwner(github),
+		GitHubHost:     githubHost,
+		GitHubRepo:     githubRepo,
+		Module:         goModule,
+		CodeOwners:     codeOwners,
+		IssueTemplates: issueTemplates,
+		ClaudeActions:  claudeActions,
+		Release:        release,
+		AddOns:         addOns,
+		Helm:           helm,
+		Dagger:         dagger,
+		DenySecrets:    denySecrets,
+		CIMatrix:       matrix,
 		Overwrite:      overwrite,
-		DryRun:         viper.GetBool("dry-run"),
-		Verbose:        viper.GetBool("verbose"),
-		Integration:    true, // Always integration mode for init
 	}
 
-	if config.Verbose {
-		fmt.Printf("Initializing Claude Code optimization for: %s\n", projectName)
-		fmt.Printf("Description: %s\n", config.Description)
-		if config.GitHubUsername != "" {
-			fmt.Printf("GitHub integration: %s\n", config.GitHubUsername)
+	ctx := context.Background()
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	plan, err := engine.Plan(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan Claude Code optimization: %w", err)
+	}
+
+	project := plan.Project
+	if viper.GetBool("verbose") {
+		printer.Printf("Initializing Claude Code optimization for: %s\n", projectName)
+		printer.Printf("Description: %s\n", project.Description)
+		if project.GitHubUsername != "" {
+			printer.Printf("GitHub integration: %s/%s/%s\n", project.GitHubHost, project.GitHubUsername, project.GitHubRepo)
+		}
+	}
+
+	var skipped []string
+	for _, file := range plan.Files {
+		report.AddFile(file.Path, file.Action)
+		if file.Action == cc.ActionSkipped {
+			skipped = append(skipped, file.Path)
 		}
 	}
 
-	if config.DryRun {
-		fmt.Println("DRY RUN - No files will be created")
+	if len(skipped) > 0 {
+		report.Warn("Found existing files: %v", skipped)
+		report.Warn("Use --overwrite to replace existing files, or run with different flags to add missing files")
+	}
+
+	// Secrets are worth a warning, not a failed run
+	for _, finding := range secretFindings(plan.Findings) {
+		report.Warn("%s", finding)
+	}
+	if len(plan.CredentialFiles) > 0 && !denySecrets {
+		report.Warn("Found credential files %v; use --deny-secrets to stop Claude Code reading them", plan.CredentialFiles)
+	}
+
+	// Check git before touching anything, so a dry run reports problems too
+	var repo *gitutil.Repo
+	if gitBranch != "" || gitCommit {
+		if repo, err = gitutil.Open(dir); err != nil {
+			return err
+		}
+		if err := checkClean(repo); err != nil {
+			if !allowDirty {
+				return fmt.Errorf("%w: commit or stash them first, or use --allow-dirty", err)
+			}
+			report.Warn("%v; only the files cc touches will be committed", err)
+		}
+	}
+
+	if viper.GetBool("dry-run") {
+		// Listing the plan is the whole point of a dry run
+		printer.Verbose = true
+		report.Summary = "DRY RUN - No files will be created"
 		return nil
 	}
 
+	if gitBranch != "" {
+		if err := repo.CreateBranch(gitBranch); err != nil {
+			return fmt.Errorf("failed to create branch: %w", err)
+		}
+	}
+	// A failed run leaves the user on the branch they started from
+	abandonBranch := func(err error) error {
+		if gitBranch == "" {
+			return err
+		}
+		if branchErr := repo.AbandonBranch(gitBranch); branchErr != nil {
+			return fmt.Errorf("%w (removing branch %s also failed: %v)", err, gitBranch, branchErr)
+		}
+		return err
+	}
+
 	// Initialize Claude Code optimization
-	if err := gen.InitializeProject(config); err != nil {
-		return fmt.Errorf("failed to initialize Claude Code optimization: %w", err)
+	if err := engine.Apply(ctx, plan); err != nil {
+		return abandonBranch(fmt.Errorf("failed to initialize Claude Code optimization: %w", err))
 	}
 
-	fmt.Printf("✅ Successfully initialized Claude Code optimization for %s\n", projectName)
-	fmt.Println("\nNext steps:")
-	fmt.Println("1. Review the generated CLAUDE.md file")
-	fmt.Println("2. Check the .claude/ directory for examples")
-	fmt.Println("3. Run 'claude' to start using Claude Code")
+	// Only now does git see the .gitignore cc may have just written
+	if len(plan.CredentialFiles) > 0 {
+		if gitRepo, err := gitutil.Open(dir); err == nil {
+			// The files are written by now, so a failed check only warns
+			exposed, err := exposedCredentialFiles(gitRepo, plan.CredentialFiles)
+			if err != nil {
+				report.Warn("Could not check whether credential files are git-ignored: %v", err)
+			}
+			for _, finding := range exposed {
+				report.Warn("%s", finding)
+			}
+		}
+	}
+
+	var commit string
+	if gitCommit {
+		if len(changedFiles(plan)) == 0 {
+			report.Warn("No files changed, nothing to commit")
+		} else if commit, err = commitPlan(repo, plan); err != nil {
+			// Roll back rather than leave the generated files uncommitted
+			if _, undoErr := engine.Undo(ctx, dir); undoErr != nil {
+				return fmt.Errorf("failed to commit changes: %w (undo also failed: %v)", err, undoErr)
+			}
+			return abandonBranch(fmt.Errorf("failed to commit changes: %w", err))
+		}
+	}
+
+	report.Summary = fmt.Sprintf("✅ Successfully initialized Claude Code optimization for %s", projectName)
+	if commit != "" {
+		branch, _ := repo.CurrentBranch()
+		report.Summary += fmt.Sprintf(" (committed %s on %s)", commit, branch)
+	}
+	report.NextSteps = append(report.NextSteps,
+		"Review the generated CLAUDE.md file",
+		"Check the .claude/ directory for examples",
+		"Run 'claude' to start using Claude Code",
+	)
 	
-	if config.GitHubUsername != "" {
-		fmt.Println("4. Commit and push your changes to GitHub")
+	if commit != "" {
+		report.NextSteps = append(report.NextSteps, "Push your branch when you're happy with the commit")
+	} else if project.GitHubUsername != "" {
+		report.NextSteps = append(report.NextSteps, "Commit and push your changes to GitHub")
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// parseCodeOwners parses PATTERN=OWNER[,OWNER...] rules.
+func parseCodeOwners(values []string) ([]cc.CodeOwnerRule, error) {
+	var rules []cc.CodeOwnerRule
+	for _, value := range values {
+		pattern, owners, ok := strings.Cut(value, "=")
+		if !ok || pattern == "" || owners == "" {
+			return nil, usageError(fmt.Errorf("invalid CODEOWNERS rule %q (want PATTERN=OWNER[,OWNER...])", value))
+		}
+		rules = append(rules, cc.CodeOwnerRule{Pattern: pattern, Owners: strings.Split(owners, ",")})
+	}
+	return rules, nil
+}
+
+// parseMatrix parses NAME=VALUE[,VALUE...] CI matrix axes.
+func parseMatrix(values []string) ([]cc.MatrixAxis, error) {
+	var axes []cc.MatrixAxis
+	for _, value := range values {
+		name, list, ok := strings.Cut(value, "=")
+		if !ok || name == "" || list == "" {
+			return nil, usageError(fmt.Errorf("invalid CI matrix axis %q (want NAME=VALUE[,VALUE...])", value))
+		}
+		axes = append(axes, cc.MatrixAxis{Name: name, Values: strings.Split(list, ",")})
+	}
+	return axes, nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitGitBranchAndCommit(t *testing.T) {
+	dir := newGitRepo(t, map[string]string{"README.md": "# Example\n"})
+
+	report, err := execute(t, "init", "--git-branch=chore/claude-code", "--git-commit", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report.Summary, "on chore/claude-code") {
+		t.Errorf("summary = %q", report.Summary)
+	}
+
+	if branch := git(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "chore/claude-code" {
+		t.Errorf("branch = %q", branch)
+	}
+	if subject := git(t, dir, "log", "-1", "--format=%s"); subject != "chore: add Claude Code optimization" {
+		t.Errorf("commit subject = %q", subject)
+	}
+	committed := git(t, dir, "show", "--name-only", "--format=", "HEAD")
+	for _, path := range []string{"CLAUDE.md", ".claude/.cc-manifest.json"} {
+		if !strings.Contains(committed, path) {
+			t.Errorf("commit does not contain %s:\n%s", path, committed)
+		}
+	}
+	if status := git(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("working tree not clean after commit:\n%s", status)
+	}
+	if log := git(t, dir, "log", "--format=%s", "main"); log != "initial" {
+		t.Errorf("main changed:\n%s", log)
+	}
+}
+
+func TestInitGitCommitRequiresCleanTree(t *testing.T) {
+	dir := newGitRepo(t, map[string]string{"README.md": "# Example\n"})
+	writeFiles(t, dir, map[string]string{"notes.txt": "mine\n"})
+
+	if _, err := execute(t, "init", "--git-branch=chore/claude-code", "--git-commit", dir); ExitCode(err) != ExitError {
+		t.Fatalf("dirty tree: err = %v, want a failure", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); !os.IsNotExist(err) {
+		t.Errorf("CLAUDE.md written despite the dirty tree: %v", err)
+	}
+	if branch := git(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("branch = %q, want main", branch)
+	}
+
+	// With --allow-dirty the user's own changes stay out of the commit
+	if _, err := execute(t, "init", "--git-commit", "--allow-dirty", dir); err != nil {
+		t.Fatal(err)
+	}
+	if committed := git(t, dir, "show", "--name-only", "--format=", "HEAD"); strings.Contains(committed, "notes.txt") {
+		t.Errorf("notes.txt was committed:\n%s", committed)
+	}
+	if status := git(t, dir, "status", "--porcelain"); status != "?? notes.txt" {
+		t.Errorf("status = %q, want only notes.txt untracked", status)
+	}
+}
+
+func TestInitGitCommitDryRun(t *testing.T) {
+	dir := newGitRepo(t, map[string]string{"README.md": "# Example\n"})
+
+	if _, err := execute(t, "init", "--dry-run", "--git-branch=chore/claude-code", "--git-commit", dir); err != nil {
+		t.Fatal(err)
+	}
+	if branches := git(t, dir, "branch", "--format=%(refname:short)"); branches != "main" {
+		t.Errorf("branches = %q, want only main", branches)
+	}
+	if status := git(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("dry run changed the tree:\n%s", status)
+	}
+}
+
+func TestInitGitHubOwner(t *testing.T) {
+	tests := []struct {
+		flag  string
+		owner string
+	}{
+		{flag: "--github", owner: "acme"},
+		{flag: "--github=octocat", owner: "octocat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flag, func(t *testing.T) {
+			dir := newGitRepo(t, map[string]string{"README.md": "# Widgets\n"})
+			git(t, dir, "remote", "add", "origin", "git@github.com:acme/widgets.git")
+
+			if _, err := execute(t, "init", tt.flag, dir); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, ".github", "CODEOWNERS"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "* @" + tt.owner + "\n"; !strings.Contains(string(data), want) {
+				t.Errorf("CODEOWNERS has no %q:\n%s", want, data)
+			}
+		})
+	}
+}
+
+func TestInitGitCommitFailureRestoresBranch(t *testing.T) {
+	dir := newGitRepo(t, map[string]string{"README.md": "# Example\n"})
+	writeFiles(t, dir, map[string]string{".git/hooks/pre-commit": "#!/bin/sh\nexit 1\n"})
+	if err := os.Chmod(filepath.Join(dir, ".git", "hooks", "pre-commit"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := execute(t, "init", "--git-branch=chore/claude-code", "--git-commit", dir); ExitCode(err) != ExitError {
+		t.Fatalf("err = %v, want a failure from the pre-commit hook", err)
+	}
+	if branch := git(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Errorf("branch = %q, want main", branch)
+	}
+	if branches := git(t, dir, "branch", "--format=%(refname:short)"); branches != "main" {
+		t.Errorf("branches = %q, want only main", branches)
+	}
+	if status := git(t, dir, "status", "--porcelain"); status != "" {
+		t.Errorf("failed run left changes:\n%s", status)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <path>",
+	Short: "Create a new project of a given type",
+	Long: `New creates a project directory with a runnable scaffold for the chosen
+type, plus everything cc init adds: CLAUDE.md, .claude/, a Makefile, CI
+workflows and pre-commit hooks.
+
+The directory must not exist or be empty. The project name defaults to the
+directory's name.
+
+Project types:
+` + projectTypeList(),
+	Example: `  cc new billing --type python-fastapi         # Create ./billing
+  cc new api --type go --module github.com/acme/api
+  cc new api -t go --with docker,helm,terraform # With add-ons
+  cc new services/api -t python-fastapi -g=acme # With GitHub integration`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+			return usageError(err)
+		}
+		return nil
+	},
+	RunE: runNew,
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+
+	newCmd.Flags().StringVarP(&projectType, "type", "t", "", "Project type (required)")
+	newCmd.Flags().StringVarP(&name, "name", "n", "", "Project name (default is the directory name)")
+	newCmd.Flags().StringVarP(&description, "description", "d", "", "Project description")
+	githubFlag(newCmd, &github)
+	newCmd.Flags().StringVar(&githubHost, "github-host", "", "GitHub host, for GitHub Enterprise (default from the git remote, or github.com)")
+	newCmd.Flags().StringVar(&githubRepo, "github-repo", "", "GitHub repository name (default from the git remote, or the project name)")
+	newCmd.Flags().StringVar(&goModule, "module", "", "Go module path for the go type (default from the git remote)")
+	newCmd.Flags().StringArrayVar(&codeOwnerRules, "codeowner", nil, "CODEOWNERS rule as PATTERN=OWNER[,OWNER...] (repeatable)")
+	newCmd.Flags().StringVar(&issueTemplates, "issue-templates", cc.IssueTemplatesForms, "GitHub issue templates: forms (YAML issue forms) or markdown")
+	newCmd.Flags().BoolVar(&claudeActions, "claude-actions", false, "Add GitHub workflows for Claude pull request review and @claude mentions")
+	newCmd.Flags().StringSliceVar(&withAddOns, "with", nil, "Add-ons to layer onto the project type, e.g. docker,helm (see cc add --help)")
+	newCmd.Flags().BoolVar(&helm, "helm", false, "Add a Helm chart with a values schema (same as --with helm)")
+	newCmd.Flags().BoolVar(&dagger, "dagger", false, "Run CI through a Dagger pipeline in Go (same as --with dagger)")
+	newCmd.Flags().BoolVar(&denySecrets, "deny-secrets", false, "Deny Claude Code reading .env and other credential files in .claude/settings.json")
+	newCmd.Flags().BoolVar(&release, "release", false, "Add changelog and release tooling (GoReleaser for Go), make release and a /release command")
+}
+
+func knownProjectType(name string) bool {
+	for _, t := range cc.ProjectTypes() {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// projectTypeList describes the built-in project types for help output.
+func projectTypeList() string {
+	var b strings.Builder
+	for _, t := range cc.ProjectTypes() {
+		fmt.Fprintf(&b, "  %-16s %s\n", t.Name, t.Description)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("new")
+
+	return emit(printer, report, newProject(printer, report, args[0]))
+}
+
+func newProject(printer *output.Printer, report *output.Report, dir string) error {
+	if projectType == "" {
+		return usageError(fmt.Errorf("--type is required"))
+	}
+
+	entries, err := os.ReadDir(dir)
+	switch {
+	case err == nil && len(entries) > 0:
+		return usageError(fmt.Errorf("%s already exists and is not empty; use cc init to add to an existing project", dir))
+	case err != nil && !os.IsNotExist(err):
+		return usageError(fmt.Errorf("invalid project directory: %w", err))
+	}
+
+	dryRun := viper.GetBool("dry-run")
+	created := !dryRun && err != nil
+	if created {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create project directory: %w", err)
+		}
+	}
+
+	if err := generateProject(printer, report, dir); err != nil {
+		if created {
+			// The directory is new, so nothing of the user's is in it
+			os.RemoveAll(dir)
+		}
+		return err
+	}
+
+	if !dryRun {
+		report.Summary = fmt.Sprintf("✅ Created %s project in %s", projectType, dir)
+		report.NextSteps = append([]string{
+			fmt.Sprintf("cd %s && git init", dir),
+			"Run 'make install' and 'make test'",
+		}, report.NextSteps...)
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewCodeOwnersMatchScaffoldedFiles(t *testing.T) {
+	tests := []struct {
+		projectType string
+		codeowner   string
+	}{
+		{projectType: "go", codeowner: "/internal/=@acme/go"},
+		{projectType: "python-fastapi", codeowner: "/app/=@acme/api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.projectType, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "service")
+
+			if _, err := execute(t, "new", dir, "--type", tt.projectType, "--codeowner", tt.codeowner); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(filepath.Join(dir, ".github", "CODEOWNERS"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pattern, owner, _ := strings.Cut(tt.codeowner, "=")
+			if !strings.Contains(string(data), pattern+" "+owner) {
+				t.Errorf("CODEOWNERS has no rule for %s:\n%s", pattern, data)
+			}
+		})
+	}
+}
+
+func TestNewGoModuleInsideRepository(t *testing.T) {
+	repo := newGitRepo(t, map[string]string{"README.md": "# Platform\n"})
+	git(t, repo, "remote", "add", "origin", "git@github.com:acme/platform.git")
+	dir := filepath.Join(repo, "services", "api")
+
+	if _, err := execute(t, "new", dir, "--type", "go"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "module github.com/acme/platform/services/api\n"; !strings.HasPrefix(string(data), want) {
+		t.Errorf("go.mod starts %q, want %q", strings.SplitN(string(data), "\n", 2)[0], want)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/onprema/cc/internal/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,8 +24,20 @@ to make your project work seamlessly with Claude Code.
 Examples:
   cc init                                   # Add Claude Code optimization to current project
   cc init --github=username                # Add GitHub integration
-  cc init --description="My awesome project" # Add project description`,
-	Version: version,
+  cc init --description="My awesome project" # Add project description
+
+Output:
+  --output=json or --output=yaml prints a single report document on stdout
+  and sends human-readable logs to stderr.
+
+Exit codes:
+  0  success
+  1  the command failed (any partial changes were rolled back)
+  2  invalid usage, such as an unknown flag or bad argument
+  3  a check found problems (for example cc doctor)`,
+	Version:       version,
+	SilenceUsage:  true,
+	SilenceErrors: true,
 }
 
 func Execute() error {
@@ -37,9 +50,87 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cc-init.yaml)")
 	rootCmd.PersistentFlags().Bool("dry-run", false, "show what would be created without creating")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().String("output", output.FormatText, "output format: text, json or yaml")
 
 	viper.BindPFlag("dry-run", rootCmd.PersistentFlags().Lookup("dry-run"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintln(os.Stderr, cmd.UsageString())
+		return usageError(err)
+	})
+}
+
+func newPrinter() (*output.Printer, error) {
+	printer, err := output.NewPrinter(viper.GetString("output"), viper.GetBool("verbose"))
+	if err != nil {
+		return nil, usageError(err)
+	}
+	return printer, nil
+}
+
+// githubFromRemote is the value of a --github flag given without an owner.
+// It cannot be a GitHub account name.
+const githubFromRemote = "-"
+
+// githubFlag adds the --github flag, which enables GitHub integration for the
+// given owner, or with no value for the owner of the repository's GitHub
+// remote.
+func githubFlag(cmd *cobra.Command, value *string) {
+	cmd.Flags().StringVarP(value, "github", "g", "", "Enable GitHub integration for `OWNER` (--github=OWNER); with no value the owner comes from the git remote")
+	cmd.Flags().Lookup("github").NoOptDefVal = githubFromRemote
+}
+
+// githubOwner returns the owner given to --github, empty if it is to come
+// from the remote.
+func githubOwner(value string) string {
+	if value == githubFromRemote {
+		return ""
+	}
+	return value
+}
+
+// projectArgs accepts an optional project directory argument.
+func projectArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+		return usageError(err)
+	}
+	return nil
+}
+
+// projectDir returns the project directory given on the command line, "." by
+// default, and checks that it is a directory.
+func projectDir(args []string) (string, error) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", usageError(fmt.Errorf("invalid project directory: %w", err))
+	}
+	if !info.IsDir() {
+		return "", usageError(fmt.Errorf("invalid project directory: %s is not a directory", dir))
+	}
+
+	return dir, nil
+}
+
+// emit prints the report, marking it as failed if err is set. In text mode a
+// failed report is not printed; the error alone is reported by main.
+func emit(printer *output.Printer, report *output.Report, err error) error {
+	if err != nil {
+		report.Status = output.StatusError
+		report.Error = err.Error()
+		if printer.Structured() {
+			printer.Emit(report)
+		}
+		return err
+	}
+
+	return printer.Emit(report)
 }
 
 func initConfig() {
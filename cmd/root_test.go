@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// execute runs cc with args and --output=json and returns the report it
+// printed, if any, and the error Execute returned. Flags are reset first, as
+// the commands keep them in package variables.
+func execute(t *testing.T, args ...string) (*output.Report, error) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	resetFlags(rootCmd)
+
+	read, write, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = write
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(read)
+		done <- data
+	}()
+
+	rootCmd.SetArgs(append([]string{"--output=json"}, args...))
+	err = rootCmd.Execute()
+	write.Close()
+	data := <-done
+
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil, err
+	}
+	var report output.Report
+	if jsonErr := json.Unmarshal(data, &report); jsonErr != nil {
+		t.Fatalf("cc %s printed invalid JSON: %v\n%s", strings.Join(args, " "), jsonErr, data)
+	}
+	return &report, err
+}
+
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, child := range cmd.Commands() {
+		resetFlags(child)
+	}
+}
+
+// writeFiles creates files, given by slash separated paths, under dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newGitRepo returns a directory holding a git repository with files
+// committed, using a git that ignores the user's and system's configuration.
+func newGitRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	git(t, dir, "init", "-q", "-b", "main")
+	git(t, dir, "add", "-A")
+	git(t, dir, "commit", "-q", "-m", "initial")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		args func(t *testing.T) []string
+		code int
+	}{
+		{
+			name: "success",
+			args: func(t *testing.T) []string { return []string{"init", t.TempDir()} },
+			code: ExitOK,
+		},
+		{
+			name: "unknown flag",
+			args: func(t *testing.T) []string { return []string{"init", "--no-such-flag", t.TempDir()} },
+			code: ExitUsage,
+		},
+		{
+			name: "too many arguments",
+			args: func(t *testing.T) []string { return []string{"init", t.TempDir(), t.TempDir()} },
+			code: ExitUsage,
+		},
+		{
+			name: "missing directory",
+			args: func(t *testing.T) []string { return []string{"init", filepath.Join(t.TempDir(), "missing")} },
+			code: ExitUsage,
+		},
+		{
+			name: "bad codeowner rule",
+			args: func(t *testing.T) []string { return []string{"init", "--codeowner=docs", t.TempDir()} },
+			code: ExitUsage,
+		},
+		{
+			name: "fleet without repositories",
+			args: func(t *testing.T) []string { return []string{"fleet", "apply"} },
+			code: ExitUsage,
+		},
+		{
+			name: "git commit outside a repository",
+			args: func(t *testing.T) []string { return []string{"init", "--git-commit", t.TempDir()} },
+			code: ExitError,
+		},
+		{
+			name: "check failed",
+			args: func(t *testing.T) []string {
+				dir := t.TempDir()
+				writeFiles(t, dir, map[string]string{"main.go": "package main\n\nvar dsn = os.Getenv(\"DATABASE_URL\")\n"})
+				return []string{"env", "check", dir}
+			},
+			code: ExitCheckFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := execute(t, tt.args(t)...)
+			if code := ExitCode(err); code != tt.code {
+				t.Fatalf("exit code = %d (%v), want %d", code, err, tt.code)
+			}
+			// Every command that gets as far as running reports its outcome
+			if report == nil {
+				if tt.code != ExitUsage {
+					t.Fatal("no report printed")
+				}
+				return
+			}
+			wantStatus := output.StatusOK
+			if tt.code != ExitOK {
+				wantStatus = output.StatusError
+			}
+			if report.Status != wantStatus || report.Version != output.SchemaVersion {
+				t.Errorf("report status = %q, version %d, want %q", report.Status, report.Version, wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/onprema/cc/internal/gitutil"
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [path]",
+	Short: "Scan the project for secrets and exposed credential files",
+	Long: `Scan looks for secrets in the project: private keys, cloud and API tokens in
+common formats (AWS, GitHub, GitLab, Anthropic, OpenAI, Slack, Stripe, Google)
+and random-looking values assigned to names like password, token or api_key.
+
+In a git repository only the files git would commit are scanned, so ignored
+files such as .env are not reported. Credential files (.env, private keys,
+Terraform state and the like) are reported if git tracks them or does not
+ignore them.
+
+With --deny, rules that stop Claude Code reading every credential file in the
+project are added to .claude/settings.json. The project is generated again with
+the options cc recorded, like cc add, and the change can be reverted with cc undo.
+
+To accept a line the scanner reports, add a ` + cc.ScanIgnoreComment + ` comment to it.
+
+Scan exits with code 3 if it finds anything.`,
+	Example: `  cc scan                                    # Scan the current project
+  cc scan --deny                             # Also deny Claude reading credential files
+  cc scan --output=json                      # Machine-readable findings`,
+	Args: projectArgs,
+	RunE: runScan,
+}
+
+var scanDeny bool
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().BoolVar(&scanDeny, "deny", false, "Add deny rules for credential files to .claude/settings.json")
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("scan")
+
+	return emit(printer, report, scan(printer, report, args))
+}
+
+func scan(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	// Outside git every file is scanned
+	var paths []string
+	repo, repoErr := gitutil.Open(dir)
+	if repoErr == nil {
+		if paths, err = repo.Files(); err != nil {
+			return fmt.Errorf("failed to list files: %w", err)
+		}
+		if paths == nil {
+			paths = []string{}
+		}
+	}
+
+	result, err := engine.Scan(ctx, dir, cc.ScanOptions{Paths: paths})
+	if err != nil {
+		return fmt.Errorf("failed to scan: %w", err)
+	}
+
+	report.Findings = secretFindings(result.Findings)
+	if repoErr == nil {
+		exposed, err := exposedCredentialFiles(repo, result.CredentialFiles)
+		if err != nil {
+			return err
+		}
+		report.Findings = append(report.Findings, exposed...)
+	}
+
+	if scanDeny && len(result.CredentialFiles) > 0 {
+		if err := addDenyRules(ctx, engine, printer, report, dir); err != nil {
+			return err
+		}
+	}
+
+	if !printer.Structured() {
+		for _, finding := range report.Findings {
+			printer.Printf("  %s\n", finding)
+		}
+	}
+
+	if len(report.Findings) > 0 {
+		if !printer.Structured() {
+			for _, warning := range report.Warnings {
+				printer.Printf("⚠️  %s\n", warning)
+			}
+		}
+		if !scanDeny && len(result.CredentialFiles) > 0 {
+			printer.Printf("Run cc scan --deny to stop Claude Code reading %s\n", strings.Join(result.CredentialFiles, ", "))
+		}
+		return checkFailed(fmt.Errorf("%d scan findings", len(report.Findings)))
+	}
+
+	report.Summary = "✅ No secrets found"
+	return nil
+}
+
+// addDenyRules plans and, unless this is a dry run, applies the deny rules for
+// the project's credential files.
+func addDenyRules(ctx context.Context, engine *cc.Engine, printer *output.Printer, report *output.Report, dir string) error {
+	plan, err := engine.PlanDenySecrets(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to plan deny rules: %w", err)
+	}
+
+	denied := true
+	for _, file := range plan.Files {
+		if file.Action == cc.ActionUnchanged {
+			continue
+		}
+		report.AddFile(file.Path, file.Action)
+		if file.Action == cc.ActionSkipped {
+			report.Warn("Kept %s, which cc could not update; add the deny rules by hand", file.Path)
+			if file.Path == ".claude/settings.json" {
+				denied = false
+			}
+		}
+	}
+
+	if viper.GetBool("dry-run") {
+		printer.Verbose = true
+		return nil
+	}
+	if err := engine.Apply(ctx, plan); err != nil {
+		return fmt.Errorf("failed to add deny rules: %w", err)
+	}
+	if denied {
+		printer.Printf("Denied Claude Code reading %s in .claude/settings.json\n", strings.Join(plan.CredentialFiles, ", "))
+	}
+	return nil
+}
+
+func secretFindings(findings []cc.Finding) []output.Finding {
+	converted := make([]output.Finding, 0, len(findings))
+	for _, finding := range findings {
+		message := "possible secret " + finding.Secret
+		if finding.Rule == "credential-file" {
+			message = "credential file referenced from CLAUDE.md"
+		}
+		converted = append(converted, output.Finding{
+			Path:    finding.Path,
+			Line:    finding.Line,
+			Rule:    finding.Rule,
+			Message: message,
+		})
+	}
+	return converted
+}
+
+// exposedCredentialFiles reports the credential files that git tracks or
+// does not ignore.
+func exposedCredentialFiles(repo *gitutil.Repo, files []string) ([]output.Finding, error) {
+	tracked, err := repo.Tracked(files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check tracked files: %w", err)
+	}
+	ignored, err := repo.Ignored(files...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ignored files: %w", err)
+	}
+
+	var findings []output.Finding
+	for _, file := range files {
+		switch {
+		case contains(tracked, file):
+			findings = append(findings, output.Finding{
+				Path:    file,
+				Rule:    "credential-file-tracked",
+				Message: "credential file is tracked by git; remove it with git rm --cached and rotate what it holds",
+			})
+		case !contains(ignored, file):
+			findings = append(findings, output.Finding{
+				Path:    file,
+				Rule:    "credential-file-not-ignored",
+				Message: "credential file is not git-ignored; add it to .gitignore",
+			})
+		}
+	}
+	return findings, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [path]",
+	Short: "Show the state of files cc generated",
+	Long: `Status compares every file recorded in .claude/.cc-manifest.json with
+what cc originally wrote and reports it as unchanged, modified or missing.`,
+	Example: `  cc status                                  # Summarize generated files
+  cc status -v                               # List every generated file
+  cc status --output=json                    # Machine-readable status`,
+	Args: projectArgs,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("status")
+
+	return emit(printer, report, status(printer, report, args))
+}
+
+func status(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := cc.New().Status(context.Background(), dir)
+	if err != nil {
+		return fmt.Errorf("failed to check generated files: %w", err)
+	}
+
+	if len(statuses) == 0 {
+		report.Summary = "No files generated by cc in this project"
+		report.NextSteps = append(report.NextSteps, "Run 'cc init' to add Claude Code optimization")
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, status := range statuses {
+		report.AddFile(status.Path, status.State)
+		counts[status.State]++
+	}
+
+	// Changed files are what people run status for, so list them even
+	// without --verbose
+	if !printer.Verbose && !printer.Structured() {
+		for _, status := range statuses {
+			if status.State != cc.StateUnchanged {
+				printer.Printf("  %-9s %s\n", status.State, status.Path)
+			}
+		}
+	}
+
+	report.Summary = fmt.Sprintf("%d generated files: %d unchanged, %d modified, %d missing",
+		len(statuses), counts[cc.StateUnchanged], counts[cc.StateModified], counts[cc.StateMissing])
+
+	return nil
+}
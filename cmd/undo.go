@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [path]",
+	Short: "Revert the most recent cc run",
+	Long: `Undo reverts the most recent cc run in the current project.
+Files created by that run are removed and files it replaced are restored
+from the backups kept in .claude/.cc-backups/.
+
+Runs are undone one at a time, newest first.`,
+	Example: `  cc undo                                    # Revert the last run
+  cc undo --list                             # Show recorded runs
+  cc undo --dry-run                          # Show what would be reverted`,
+	Args: projectArgs,
+	RunE: runUndo,
+}
+
+var listRuns bool
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().BoolVarP(&listRuns, "list", "l", false, "List recorded runs instead of undoing")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("undo")
+
+	return emit(printer, report, undo(printer, report, args))
+}
+
+func undo(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	runs, err := engine.Runs(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	if listRuns {
+		for _, run := range runs {
+			report.Runs = append(report.Runs, output.Run{
+				ID:       run.ID,
+				Time:     run.Time.Format(time.RFC3339),
+				Created:  len(run.Created),
+				Replaced: len(run.Replaced),
+			})
+			if !printer.Structured() {
+				printer.Printf("%s  %s  %d created, %d replaced\n",
+					run.ID, run.Time.Local().Format("2006-01-02 15:04:05"), len(run.Created), len(run.Replaced))
+			}
+		}
+		if len(runs) == 0 {
+			report.Summary = "No recorded runs"
+		}
+		return nil
+	}
+
+	if len(runs) == 0 {
+		return fmt.Errorf("nothing to undo: no runs recorded in %s", cc.BackupDir)
+	}
+
+	if viper.GetBool("dry-run") {
+		addRunFiles(report, &runs[0])
+		printer.Verbose = true
+		report.Summary = fmt.Sprintf("DRY RUN - would undo run %s", runs[0].ID)
+		return nil
+	}
+
+	run, err := engine.Undo(ctx, dir)
+	if err != nil {
+		return fmt.Errorf("failed to undo: %w", err)
+	}
+
+	addRunFiles(report, run)
+	report.Summary = fmt.Sprintf("✅ Undid run %s (%d removed, %d restored)", run.ID, len(run.Created), len(run.Replaced))
+
+	return nil
+}
+
+func addRunFiles(report *output.Report, run *cc.Run) {
+	for _, path := range run.Created {
+		report.AddFile(path, "removed")
+	}
+	for _, path := range run.Replaced {
+		report.AddFile(path, "restored")
+	}
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onprema/cc/internal/output"
+	"github.com/onprema/cc/pkg/cc"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall [path]",
+	Short: "Remove everything cc generated in the current project",
+	Long: `Uninstall removes the files cc generated, using the manifest in
+.claude/.cc-manifest.json to tell generated files from edited ones.
+
+Files unchanged since generation are deleted and cc managed blocks are
+stripped from merged files such as .gitignore and Makefile. Files you have
+modified are listed and kept unless --force is specified.`,
+	Example: `  cc uninstall                               # Remove unchanged generated files
+  cc uninstall --dry-run                     # Show what would be removed
+  cc uninstall --force                       # Also remove modified files`,
+	Args: projectArgs,
+	RunE: runUninstall,
+}
+
+var force bool
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+
+	uninstallCmd.Flags().BoolVarP(&force, "force", "f", false, "Remove generated files even if they were modified")
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	printer, err := newPrinter()
+	if err != nil {
+		return err
+	}
+	report := output.NewReport("uninstall")
+
+	return emit(printer, report, uninstall(printer, report, args))
+}
+
+func uninstall(printer *output.Printer, report *output.Report, args []string) error {
+	dir, err := projectDir(args)
+	if err != nil {
+		return err
+	}
+
+	dryRun := viper.GetBool("dry-run")
+
+	engine := cc.New(cc.WithLog(printer.Log()))
+
+	result, err := engine.Uninstall(context.Background(), dir, cc.UninstallOptions{Force: force, DryRun: dryRun})
+	if err != nil {
+		return fmt.Errorf("failed to uninstall: %w", err)
+	}
+
+	for _, path := range result.Removed {
+		report.AddFile(path, "removed")
+	}
+	for _, path := range result.Stripped {
+		report.AddFile(path, "stripped")
+	}
+	for _, path := range result.Modified {
+		report.AddFile(path, "kept")
+	}
+	for _, path := range result.Missing {
+		report.AddFile(path, "missing")
+	}
+
+	// Removals are the point of this command, so always list them
+	printer.Verbose = true
+
+	if len(result.Modified) > 0 {
+		report.Warn("Kept %d files modified since generation: %v", len(result.Modified), result.Modified)
+		report.NextSteps = append(report.NextSteps, "Use --force to remove modified files as well")
+	}
+
+	if dryRun {
+		report.Summary = "DRY RUN - No files will be removed"
+	} else {
+		report.Summary = fmt.Sprintf("✅ Removed %d files and cleaned %d merged files", len(result.Removed), len(result.Stripped))
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package diff renders line-based unified diffs of generated files.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around each change.
+const context = 3
+
+type op struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// Unified returns a unified diff turning from into to, or "" if they are
+// equal. An empty fromName is rendered as /dev/null, for new files.
+func Unified(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	if fromName == "" {
+		fromName = "/dev/null"
+	}
+
+	ops := edits(splitLines(from), splitLines(to))
+
+	// Line numbers in from and to before each op
+	fromPos := make([]int, len(ops)+1)
+	toPos := make([]int, len(ops)+1)
+	for i, o := range ops {
+		fromPos[i+1], toPos[i+1] = fromPos[i], toPos[i]
+		if o.kind != '+' {
+			fromPos[i+1]++
+		}
+		if o.kind != '-' {
+			toPos[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		start := max(0, i-context)
+		end := i
+		for {
+			for end < len(ops) && ops[end].kind != ' ' {
+				end++
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			// Merge changes separated by less than two contexts into one hunk
+			if next < len(ops) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			end = min(len(ops), end+context)
+			break
+		}
+
+		fromCount := fromPos[end] - fromPos[start]
+		toCount := toPos[end] - toPos[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(fromPos[start], fromCount), hunkRange(toPos[start], toCount))
+		for _, o := range ops[start:end] {
+			fmt.Fprintf(&b, "%c%s\n", o.kind, o.line)
+		}
+
+		i = end
+	}
+
+	return b.String()
+}
+
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// edits computes a shortest edit script from a to b using the longest common
+// subsequence. Generated files are small, so the quadratic table is fine.
+func edits(a, b []string) []op {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, op{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, op{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, op{'+', b[j]})
+	}
+
+	return ops
+}
@@ -0,0 +1,212 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pinnedAction is the release of a GitHub Action that generated workflows
+// use. Both fields change together when an action is bumped.
+type pinnedAction struct {
+	Version string
+	SHA     string
+}
+
+// actionVersions pins the actions in generated workflows to a full commit SHA,
+// so a moved tag cannot change what CI runs. TestActionVersions checks that
+// every action passed to uses is here, and that each has a SHA unless it is
+// listed as not yet pinned; those are marked as unpinned in the workflows.
+var actionVersions = map[string]pinnedAction{
+	"actions/cache":                 {Version: "v4.2.3", SHA: "5a3ec84eff668545956fd18022155c47e93e2684"},
+	"actions/checkout":              {Version: "v4.2.2", SHA: "11bd71901bbe5b1630ceea73d27597364c9af683"},
+	"actions/setup-go":              {Version: "v5.5.0", SHA: "d35c59abb061a4a6fb18e82ac0862c26744d6ab5"},
+	"actions/setup-node":            {Version: "v4.4.0", SHA: "49933ea5288caeca8642d1e84afbd3f7d6820020"},
+	"actions/setup-python":          {Version: "v5.6.0", SHA: "a26af69be951a213d495a4c3e4e4022e16d87065"},
+	"anthropics/claude-code-action": {Version: "v1"},
+	"hashicorp/setup-terraform":     {Version: "v3.1.2", SHA: "b9cd54a3c349d3f38e8881555d616ced269862dd"},
+}
+
+// uses returns the value of a workflow step's uses: field for action, which
+// must be in actionVersions.
+func uses(action string) string {
+	pin, ok := actionVersions[action]
+	if !ok {
+		panic(fmt.Sprintf("no version for action %s", action))
+	}
+	if pin.SHA == "" {
+		return action + "@" + pin.Version + " # not pinned to a commit SHA yet; pin it before relying on it"
+	}
+	return action + "@" + pin.SHA + " # " + pin.Version
+}
+
+// usesRe matches a uses: line referring to an action in another repository,
+// capturing the indentation and key with any opening quote, owner/repo, an
+// optional path inside the repository, the ref, and the rest of the line.
+var usesRe = regexp.MustCompile(`^(\s*(?:-\s+)?uses:\s*["']?)([\w.-]+/[\w.-]+)(/[^@\s"']*)?@([^\s"'#]+)(["']?(\s+#.*)?)$`)
+
+// PinResult lists what PinActions changed, or would change in a dry run.
+// Paths are relative to the project root.
+type PinResult struct {
+	Files []string
+	// Pinned counts the rewritten uses: references
+	Pinned int
+	// Unpinned lists the references left alone, as "file: action@ref (reason)"
+	Unpinned []string
+}
+
+// PinActions rewrites the uses: references in .github/workflows to the commit
+// SHAs in cc's version table. The run is recorded and can be reverted with
+// Undo, and a failure rolls everything back.
+func (g *Generator) PinActions(root string, dryRun bool) (result *PinResult, err error) {
+	workflowDir := filepath.Join(root, ".github", "workflows")
+	entries, err := g.FS.ReadDir(workflowDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows: %w", err)
+	}
+
+	g.root = root
+	g.journal = newJournal(g.FS)
+	g.run = newRun(g.FS, root, g.Now())
+	if g.manifest, err = g.LoadManifest(root); err != nil {
+		return nil, err
+	}
+	g.manifestChanged = false
+	defer func() {
+		if err != nil {
+			if rbErr := g.journal.rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	var names []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if !entry.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	result = &PinResult{}
+	for _, name := range names {
+		path := filepath.Join(workflowDir, name)
+		rel := ".github/workflows/" + name
+
+		data, err := g.FS.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+
+		content, pinned, unpinned := pinWorkflow(string(data))
+		for _, action := range unpinned {
+			result.Unpinned = append(result.Unpinned, rel+": "+action)
+		}
+		if pinned == 0 {
+			continue
+		}
+		result.Files = append(result.Files, rel)
+		result.Pinned += pinned
+
+		if dryRun {
+			continue
+		}
+		if err := g.replaceFile(path, []byte(content)); err != nil {
+			return nil, err
+		}
+
+		// A generated workflow that was unchanged stays unchanged in cc status
+		if g.generatedUnchanged(rel, string(data)) {
+			g.track(rel, content, false)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := g.saveManifest(); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := g.saveRun(); err != nil {
+		return nil, fmt.Errorf("failed to record run: %w", err)
+	}
+
+	return result, nil
+}
+
+var shaRe = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// pinWorkflow rewrites the uses: lines of a workflow, returning the new
+// content, how many references changed and the ones it left alone. Refs that
+// are already SHAs are kept. Tags are only pinned within the same major
+// version, and exact releases newer than cc's are left alone. A floating tag
+// such as v4 is pinned to cc's release, which may be older than the one the
+// tag points to today.
+func pinWorkflow(content string) (string, int, []string) {
+	lines := strings.Split(content, "\n")
+	pinned := 0
+	var unpinned []string
+
+	for i, line := range lines {
+		match := usesRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		prefix, action, subpath, ref, rest, comment := match[1], match[2], match[3], match[4], match[5], match[6]
+
+		if shaRe.MatchString(ref) {
+			continue
+		}
+
+		pin, ok := actionVersions[action]
+		switch {
+		case !ok || pin.SHA == "":
+			unpinned = append(unpinned, action+subpath+"@"+ref+" (not in cc's version table)")
+			continue
+		case majorVersion(ref) != majorVersion(pin.Version):
+			unpinned = append(unpinned, action+subpath+"@"+ref+" (cc pins "+pin.Version+")")
+			continue
+		case newerVersion(ref, pin.Version):
+			unpinned = append(unpinned, action+subpath+"@"+ref+" (newer than cc's "+pin.Version+")")
+			continue
+		}
+
+		// Only the ref changes; a comment of the user's is kept as it is
+		pinnedLine := prefix + action + subpath + "@" + pin.SHA + rest
+		if comment == "" {
+			pinnedLine += " # " + pin.Version
+		}
+		if line != pinnedLine {
+			lines[i] = pinnedLine
+			pinned++
+		}
+	}
+
+	return strings.Join(lines, "\n"), pinned, unpinned
+}
+
+// majorVersion returns the major version of a tag like v4 or v4.2.2.
+func majorVersion(tag string) string {
+	major, _, _ := strings.Cut(tag, ".")
+	return major
+}
+
+// newerVersion reports whether tag is a release after base, comparing as many
+// version components as tag has, so v4.3 is newer than v4.2.2 but v4 is not.
+func newerVersion(tag, base string) bool {
+	tagParts := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	baseParts := strings.Split(strings.TrimPrefix(base, "v"), ".")
+	for i := 0; i < len(tagParts) && i < len(baseParts); i++ {
+		t, err1 := strconv.Atoi(tagParts[i])
+		b, err2 := strconv.Atoi(baseParts[i])
+		if err1 != nil || err2 != nil || t != b {
+			return err1 == nil && err2 == nil && t > b
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestPinWorkflow(t *testing.T) {
+	checkout := actionVersions["actions/checkout"]
+	workflow := `jobs:
+  test:
+    steps:
+      - uses: actions/checkout@v4
+      - uses: "actions/setup-go@v5.0.0" # old
+      - name: Cache
+        uses: actions/cache/restore@v4
+      - uses: 'actions/cache@v4'
+      - uses: actions/checkout@v5
+      - uses: actions/checkout@0123456789abcdef0123456789abcdef01234567
+      - uses: acme/deploy@main
+      - uses: ./.github/actions/local
+      - uses: docker://alpine:3
+`
+
+	got, pinned, unpinned := pinWorkflow(workflow)
+	if pinned != 4 {
+		t.Errorf("pinned = %d, want 4", pinned)
+	}
+	for _, want := range []string{
+		"      - uses: actions/checkout@" + checkout.SHA + " # " + checkout.Version + "\n",
+		"      - uses: \"actions/setup-go@" + actionVersions["actions/setup-go"].SHA + "\" # old\n",
+		"        uses: actions/cache/restore@" + actionVersions["actions/cache"].SHA + " # ",
+		"      - uses: 'actions/cache@" + actionVersions["actions/cache"].SHA + "' # " + actionVersions["actions/cache"].Version + "\n",
+		"      - uses: actions/checkout@v5\n",
+		"@0123456789abcdef0123456789abcdef01234567\n",
+		"      - uses: ./.github/actions/local\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("pinned workflow does not contain %q:\n%s", want, got)
+		}
+	}
+
+	want := []string{"actions/checkout@v5 (cc pins " + checkout.Version + ")", "acme/deploy@main (not in cc's version table)"}
+	if strings.Join(unpinned, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unpinned = %q, want %q", unpinned, want)
+	}
+
+	if again, pinned, _ := pinWorkflow(got); again != got || pinned != 0 {
+		t.Errorf("pinning twice changed %d references", pinned)
+	}
+}
+
+func TestPinActionsKeepsGeneratedWorkflowsTracked(t *testing.T) {
+	mem := newMemFS(t, nil)
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a workflow generated by an older cc, recorded in the manifest
+	path := filepath.Join(testRoot, ".github", "workflows", "ci.yml")
+	old := "steps:\n  - uses: actions/checkout@v4\n"
+	if err := afero.WriteFile(mem, path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := gen.LoadManifest(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest.Files[".github/workflows/ci.yml"] = ManifestEntry{SHA256: hashContent(old)}
+	data, _ := manifest.marshal()
+	if err := afero.WriteFile(mem, filepath.Join(testRoot, ManifestFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := gen.PinActions(testRoot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Pinned != 1 || len(result.Files) != 1 {
+		t.Errorf("result = %+v", result)
+	}
+
+	statuses, err := gen.CheckManifest(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if status.State != StateUnchanged {
+			t.Errorf("%s is %s after pinning", status.Path, status.State)
+		}
+	}
+
+	if _, err := gen.Undo(testRoot); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := afero.ReadFile(mem, path); string(got) != old {
+		t.Errorf("undo left %q", got)
+	}
+}
+
+func TestPinWorkflowKeepsNewerReleases(t *testing.T) {
+	checkout := actionVersions["actions/checkout"]
+	workflow := "      - uses: actions/checkout@v4.9.0\n      - uses: actions/checkout@v4.1\n"
+
+	got, pinned, unpinned := pinWorkflow(workflow)
+	if pinned != 1 || !strings.Contains(got, "actions/checkout@"+checkout.SHA) {
+		t.Errorf("pinned = %d:\n%s", pinned, got)
+	}
+	want := "actions/checkout@v4.9.0 (newer than cc's " + checkout.Version + ")"
+	if len(unpinned) != 1 || unpinned[0] != want {
+		t.Errorf("unpinned = %q, want %q", unpinned, want)
+	}
+}
+
+// unpinnedActions are in actionVersions without a commit SHA yet. Each one is
+// a workflow whose action can change under it; record its SHA and remove it
+// from here.
+var unpinnedActions = map[string]bool{
+	"anthropics/claude-code-action": true,
+}
+
+func TestActionVersions(t *testing.T) {
+	versionRe := regexp.MustCompile(`^v\d+(\.\d+)*$`)
+	for action, pin := range actionVersions {
+		if !versionRe.MatchString(pin.Version) {
+			t.Errorf("%s: version %q is not a release tag", action, pin.Version)
+		}
+		if unpinnedActions[action] {
+			continue
+		}
+		if !shaRe.MatchString(pin.SHA) {
+			t.Errorf("%s: %q is not a full commit SHA", action, pin.SHA)
+		}
+	}
+
+	// uses panics for an unknown action, so every call must name one in the table
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usesCallRe := regexp.MustCompile(`\buses\("([^"]+)"\)`)
+	for _, source := range sources {
+		data, err := os.ReadFile(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range usesCallRe.FindAllStringSubmatch(string(data), -1) {
+			if _, ok := actionVersions[match[1]]; !ok {
+				t.Errorf("%s: uses(%q) is not in actionVersions", source, match[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package generator
+
+import "context"
+
+func init() {
+	mustRegisterAddOn(dockerAddOn())
+}
+
+// dockerAddOn builds a container image locally and in CI. Types that ship
+// their own Containerfile keep it.
+func dockerAddOn() AddOn {
+	return AddOn{
+		Name:        "docker",
+		Description: "Containerfile, make image and an image build in CI",
+		Layer: ProjectType{
+			MakeVars: []string{
+				"CONTAINER_ENGINE ?= podman",
+				"IMAGE ?= $(notdir $(CURDIR))",
+			},
+			MakeTargets: []MakeTarget{
+				{Name: "image", Help: "Build the container image", Recipe: []string{
+					"$(CONTAINER_ENGINE) build -t $(IMAGE) -f Containerfile .",
+				}},
+				{Name: "image-run", Help: "Run the container image", Recipe: []string{
+					"$(CONTAINER_ENGINE) run --rm -it $(IMAGE)",
+				}},
+			},
+			CIJobs: ciJob("image", "Build container image", "image CONTAINER_ENGINE=docker"),
+			Claude: `## Container Image
+
+- ` + "`Containerfile`" + ` - Image definition, built with ` + "`make image`" + ` (Podman, or
+  ` + "`make image CONTAINER_ENGINE=docker`" + `) and in CI on every pull request
+
+Keep the image small and unprivileged: multi-stage builds, a pinned base image,
+a non-root ` + "`USER`" + `, and nothing from ` + "`.containerignore`" + ` copied in.
+`,
+			Generators: []FileGenerator{generateDockerFiles},
+		},
+	}
+}
+
+// typesWithContainerfile already generate a Containerfile of their own.
+var typesWithContainerfile = []string{"go", "python-fastapi"}
+
+func generateDockerFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	if contains(typesWithContainerfile, config.Type) {
+		return nil, nil
+	}
+
+	containerfile := `# Build with: podman build -t ` + config.Name + ` -f Containerfile .
+FROM docker.io/library/alpine:3.22
+
+RUN adduser -D -u 10001 app
+WORKDIR /app
+COPY --chown=app . .
+
+USER app
+# Replace with the command that starts your application
+CMD ["sh", "-c", "echo 'Hello from ` + config.Name + `'"]
+`
+
+	containerignore := `.git/
+.env
+.venv/
+node_modules/
+dist/
+build/
+`
+
+	return []File{
+		{Path: "Containerfile", Content: containerfile},
+		{Path: ".containerignore", Content: containerignore},
+	}, nil
+}
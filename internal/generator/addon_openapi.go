@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"context"
+	"strconv"
+)
+
+// redoclyVersion is the Redocly CLI release used to lint the spec.
+const redoclyVersion = "1.34.3"
+
+func init() {
+	mustRegisterAddOn(openAPIAddOn())
+}
+
+// openAPIAddOn adds a hand-written OpenAPI document, linted in CI.
+func openAPIAddOn() AddOn {
+	redocly := "npx --yes @redocly/cli@" + redoclyVersion
+
+	return AddOn{
+		Name:        "openapi",
+		Description: "Spec-first OpenAPI 3.1 document linted with Redocly",
+		// FastAPI generates its OpenAPI document from the code
+		Conflicts: []string{"python-fastapi"},
+		Layer: ProjectType{
+			MakeTargets: []MakeTarget{
+				{Name: "api-lint", Help: "Lint openapi.yaml", Recipe: []string{redocly + " lint openapi.yaml"}},
+				{Name: "api-docs", Help: "Render the API reference to docs/api.html", Recipe: []string{
+					redocly + " build-docs openapi.yaml --output docs/api.html",
+				}},
+			},
+			GitIgnore: []string{"docs/api.html"},
+			// Node comes with the runner image
+			CIJobs: ciJob("openapi", "Lint OpenAPI document", "api-lint"),
+			Permissions: Permissions{
+				Allow: []string{"Bash(make api-lint)"},
+			},
+			Claude: `## OpenAPI
+
+- ` + "`openapi.yaml`" + ` - The API contract (OpenAPI 3.1); the source of truth for endpoints
+- ` + "`redocly.yaml`" + ` - Lint rules, run with ` + "`make api-lint`" + `
+
+The spec comes first: change ` + "`openapi.yaml`" + ` before the handlers, in the same
+pull request, and keep every operation's ` + "`operationId`" + `, responses and schemas
+accurate. Never remove or rename a field in a released version; add new ones.
+`,
+			Generators: []FileGenerator{generateOpenAPIFiles},
+		},
+	}
+}
+
+func generateOpenAPIFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	spec := `openapi: 3.1.0
+info:
+  title: ` + strconv.Quote(config.Name) + `
+  description: ` + strconv.Quote(config.Description) + `
+  version: 0.1.0
+  license:
+    name: MIT
+    identifier: MIT
+servers:
+  - url: http://localhost:8080
+    description: Local development
+paths:
+  /healthz:
+    get:
+      operationId: getHealth
+      summary: Report service health
+      tags: [health]
+      # Health checks are public
+      security: []
+      responses:
+        "200":
+          description: The service is healthy
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Health"
+        "503":
+          description: The service is unhealthy
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Health"
+tags:
+  - name: health
+    description: Service health
+components:
+  schemas:
+    Health:
+      type: object
+      required: [status]
+      properties:
+        status:
+          type: string
+          enum: [ok, unavailable]
+`
+
+	redocly := `# Redocly CLI configuration: https://redocly.com/docs/cli/configuration
+extends:
+  - recommended
+
+rules:
+  # Health checks have no client errors
+  operation-4xx-response: off
+`
+
+	return []File{
+		{Path: "openapi.yaml", Content: spec},
+		{Path: "redocly.yaml", Content: redocly},
+	}, nil
+}
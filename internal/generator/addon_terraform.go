@@ -0,0 +1,80 @@
+package generator
+
+import "context"
+
+func init() {
+	mustRegisterAddOn(terraformAddOn())
+}
+
+// terraformAddOn keeps a project's infrastructure in terraform/, validated in
+// CI. The terraform project type is the better fit for repositories that
+// hold nothing else.
+func terraformAddOn() AddOn {
+	return AddOn{
+		Name:        "terraform",
+		Description: "Terraform configuration in terraform/, validated in CI; Claude may plan but never apply",
+		Conflicts:   []string{"terraform"},
+		Layer: ProjectType{
+			MakeVars: []string{"TF_DIR ?= terraform"},
+			MakeTargets: []MakeTarget{
+				{Name: "tf-init", Help: "Initialize Terraform in TF_DIR", Recipe: []string{"terraform -chdir=$(TF_DIR) init"}},
+				{Name: "tf-plan", Help: "Plan TF_DIR and save it to tfplan", Recipe: []string{"terraform -chdir=$(TF_DIR) plan -out=tfplan"}},
+				{Name: "tf-validate", Help: "Check formatting and validate TF_DIR without a backend", Recipe: []string{
+					"terraform fmt -check -recursive $(TF_DIR)",
+					"terraform -chdir=$(TF_DIR) init -backend=false -input=false >/dev/null",
+					"terraform -chdir=$(TF_DIR) validate",
+				}},
+				{Name: "tf-format", Help: "Format the Terraform files", Recipe: []string{"terraform fmt -recursive $(TF_DIR)"}},
+			},
+			GitIgnore: terraformType().GitIgnore,
+			CIJobs: ciJob("terraform", "Validate Terraform", "tf-validate",
+				"      - uses: "+uses("hashicorp/setup-terraform")+"\n"),
+			Permissions: Permissions{
+				Allow: []string{
+					"Bash(terraform fmt:*)",
+					"Bash(terraform -chdir=terraform validate)",
+					"Bash(terraform -chdir=terraform plan:*)",
+					"Bash(make tf-plan)",
+					"Bash(make tf-validate)",
+				},
+				Deny: terraformDenyRules("terraform"),
+			},
+			Claude: `## Terraform
+
+- ` + "`terraform/`" + ` - Infrastructure for this project; ` + "`versions.tf`" + ` pins Terraform and providers
+
+Run ` + "`make tf-validate`" + ` after every change and ` + "`make tf-plan`" + ` to preview it.
+**Never run ` + "`terraform apply`" + ` or ` + "`terraform destroy`" + `**, however the command is
+spelled; a human applies reviewed plans. The deny rules in ` + "`.claude/settings.json`" + `
+match command prefixes only and are a backstop, not a hard guard.
+`,
+			Generators: []FileGenerator{generateTerraformAddOnFiles},
+		},
+	}
+}
+
+func generateTerraformAddOnFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	return []File{
+		{Path: "terraform/versions.tf", Content: `terraform {
+  required_version = "` + terraformRequiredVersion + `"
+
+  # Add required_providers here, and a backend before anyone else applies
+}
+`},
+		{Path: "terraform/variables.tf", Content: `variable "environment" {
+  description = "Deployment environment."
+  type        = string
+  default     = "dev"
+}
+`},
+		{Path: "terraform/main.tf", Content: `locals {
+  name = "` + kubernetesName(config.Name) + `-${var.environment}"
+}
+`},
+		{Path: "terraform/outputs.tf", Content: `output "name" {
+  description = "Name prefix for this environment's resources."
+  value       = local.name
+}
+`},
+	}, nil
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AddOn layers extra files, Makefile targets, CI jobs, .gitignore entries and
+// CLAUDE.md notes onto a project of any type, for example a Helm chart for a
+// Go service.
+type AddOn struct {
+	Name        string
+	Description string
+	// Conflicts are the add-ons and project types this add-on cannot be
+	// combined with. A conflict declared by either side applies both ways.
+	Conflicts []string
+	// Layer is merged into the project type; its Name is ignored
+	Layer ProjectType
+}
+
+var addOns = make(map[string]AddOn)
+
+// RegisterAddOn makes an add-on available to every Generator. Built-in
+// add-ons register themselves from init.
+func RegisterAddOn(a AddOn) error {
+	if a.Name == "" {
+		return fmt.Errorf("add-on must have a name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := addOns[a.Name]; ok {
+		return fmt.Errorf("add-on %q is already registered", a.Name)
+	}
+	addOns[a.Name] = a
+
+	return nil
+}
+
+// AddOns returns the registered add-ons sorted by name.
+func AddOns() []AddOn {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	list := make([]AddOn, 0, len(addOns))
+	for _, a := range addOns {
+		list = append(list, a)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list
+}
+
+func mustRegisterAddOn(a AddOn) {
+	if err := RegisterAddOn(a); err != nil {
+		panic(err)
+	}
+}
+
+// resolveAddOns looks up the named add-ons, dropping repeats, and checks that
+// they can be combined with each other and with the project type.
+func resolveAddOns(projectType string, names []string) ([]AddOn, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	var resolved []AddOn
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		a, ok := addOns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown add-on %q", name)
+		}
+		if projectType != "" && contains(a.Conflicts, projectType) {
+			return nil, fmt.Errorf("add-on %s conflicts with project type %s", a.Name, projectType)
+		}
+		resolved = append(resolved, a)
+	}
+
+	for i, a := range resolved {
+		for _, b := range resolved[i+1:] {
+			if contains(a.Conflicts, b.Name) || contains(b.Conflicts, a.Name) {
+				return nil, fmt.Errorf("add-ons %s and %s conflict", a.Name, b.Name)
+			}
+			for _, target := range a.Layer.MakeTargets {
+				for _, other := range b.Layer.MakeTargets {
+					if target.Name == other.Name {
+						return nil, fmt.Errorf("add-ons %s and %s both define make %s", a.Name, b.Name, target.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+// PlanAddOns plans adding add-ons to a project cc already generated in root,
+// using the settings recorded in its manifest. Generated files the user has not
+// modified are brought up to date; modified ones are skipped.
+func (g *Generator) PlanAddOns(ctx context.Context, root string, names []string) (*Plan, error) {
+	return g.replan(ctx, root, func(config *ProjectConfig) {
+		config.AddOns = appendUnique(config.AddOns, names...)
+	})
+}
+
+// replan plans generating the project in root again with the settings in its
+// manifest, as changed by update.
+func (g *Generator) replan(ctx context.Context, root string, update func(*ProjectConfig)) (*Plan, error) {
+	manifest, err := g.LoadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Project == nil {
+		return nil, fmt.Errorf("no project settings recorded in %s; run cc init first", ManifestFile)
+	}
+
+	config := manifest.Project.config(root)
+	update(config)
+	config.Refresh = true
+
+	return g.Plan(ctx, config)
+}
+
+// withAddOn layers an add-on onto a project type. The add-on's Makefile
+// targets replace the type's targets of the same name. GitIgnore entries are
+// left out, since each add-on gets its own .gitignore section.
+func withAddOn(t, addOn ProjectType) ProjectType {
+	t.IssueFields = append(append([]IssueField{}, t.IssueFields...), addOn.IssueFields...)
+	t.Stacks = appendUnique(t.Stacks, addOn.Stacks...)
+	t.MakeTargets = mergeMakeTargets(t.MakeTargets, addOn.MakeTargets)
+	t.MakeVars = appendUnique(t.MakeVars, addOn.MakeVars...)
+	t.PreCommit += addOn.PreCommit
+	t.CIJobs += addOn.CIJobs
+	t.Permissions = Permissions{
+		Allow: appendUnique(t.Permissions.Allow, addOn.Permissions.Allow...),
+		Deny:  appendUnique(t.Permissions.Deny, addOn.Permissions.Deny...),
+	}
+	if t.Claude != "" && addOn.Claude != "" {
+		t.Claude += "\n"
+	}
+	t.Claude += addOn.Claude
+	t.Generators = append(append([]FileGenerator{}, t.Generators...), addOn.Generators...)
+	return t
+}
+
+// appendUnique returns a copy of values with the extra values not already in
+// it appended.
+func appendUnique(values []string, extra ...string) []string {
+	result := append([]string{}, values...)
+	for _, value := range extra {
+		if !contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// ciJob renders a CI workflow job that checks out the code, runs the setup
+// steps and then make target, for ProjectType.CIJobs.
+func ciJob(id, name, target string, setup ...string) string {
+	var b strings.Builder
+	b.WriteString(`
+  ` + id + `:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          persist-credentials: false
+`)
+	for _, step := range setup {
+		b.WriteString("\n" + step)
+	}
+	b.WriteString(`
+      - name: ` + name + `
+        run: make ` + target + `
+`)
+	return b.String()
+}
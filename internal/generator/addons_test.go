@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestResolveAddOns(t *testing.T) {
+	registryMu.Lock()
+	saved := addOns
+	addOns = map[string]AddOn{
+		"a":     {Name: "a", Conflicts: []string{"b"}},
+		"b":     {Name: "b"},
+		"c":     {Name: "c", Conflicts: []string{"go"}},
+		"make1": {Name: "make1", Layer: ProjectType{MakeTargets: []MakeTarget{{Name: "up"}}}},
+		"make2": {Name: "make2", Layer: ProjectType{MakeTargets: []MakeTarget{{Name: "up"}}}},
+	}
+	registryMu.Unlock()
+	t.Cleanup(func() {
+		registryMu.Lock()
+		addOns = saved
+		registryMu.Unlock()
+	})
+
+	tests := []struct {
+		name        string
+		projectType string
+		addOns      []string
+		want        []string
+		wantErr     string
+	}{
+		{name: "none", want: nil},
+		{name: "repeats dropped", addOns: []string{"a", "c", "a"}, want: []string{"a", "c"}},
+		{name: "unknown", addOns: []string{"missing"}, wantErr: `unknown add-on "missing"`},
+		{name: "declared conflict", addOns: []string{"a", "b"}, wantErr: "add-ons a and b conflict"},
+		{name: "declared by the other side", addOns: []string{"b", "a"}, wantErr: "add-ons b and a conflict"},
+		{name: "conflicting type", projectType: "go", addOns: []string{"c"}, wantErr: "add-on c conflicts with project type go"},
+		{name: "generic type", addOns: []string{"c"}, want: []string{"c"}},
+		{name: "same make target", addOns: []string{"make1", "make2"}, wantErr: "add-ons make1 and make2 both define make up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := resolveAddOns(tt.projectType, tt.addOns)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names []string
+			for _, a := range resolved {
+				names = append(names, a.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("add-ons = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinAddOnsCombine(t *testing.T) {
+	var names []string
+	for _, a := range AddOns() {
+		if a.Name != "dagger" {
+			names = append(names, a.Name)
+		}
+	}
+
+	if _, err := resolveAddOns("go", names); err != nil {
+		t.Errorf("built-in add-ons %v do not combine: %v", names, err)
+	}
+}
+
+func TestPlanAddOns(t *testing.T) {
+	mem := newMemFS(t, nil)
+	gen := newTestGenerator(AferoFS{Fs: mem})
+	ctx := context.Background()
+
+	if _, err := gen.PlanAddOns(ctx, testRoot, []string{"docker"}); err == nil {
+		t.Fatal("expected an error for a project cc has not initialized")
+	}
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example", Description: "An example project"}); err != nil {
+		t.Fatal(err)
+	}
+	// A file the user has edited must survive
+	workflow := filepath.Join(testRoot, ".github", "workflows", "ci.yml")
+	if err := afero.WriteFile(mem, workflow, []byte("name: Mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := gen.PlanAddOns(ctx, testRoot, []string{"docker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := make(map[string]string)
+	for _, file := range plan.Files {
+		actions[file.Path] = file.Action
+	}
+	for path, want := range map[string]string{
+		"Containerfile":            ActionCreated,
+		"CLAUDE.md":                ActionReplaced,
+		"Makefile":                 ActionMerged,
+		".github/workflows/ci.yml": ActionSkipped,
+		".claude/README.md":        ActionUnchanged,
+	} {
+		if actions[path] != want {
+			t.Errorf("%s: action = %q, want %q", path, actions[path], want)
+		}
+	}
+
+	files := snapshot(t, mem, true)
+	if !strings.Contains(files["CLAUDE.md"], "## Container Image") {
+		t.Error("CLAUDE.md is missing the docker section")
+	}
+	if !strings.Contains(files["Makefile"], "image-run:") {
+		t.Error("Makefile is missing the docker targets")
+	}
+	if files[".github/workflows/ci.yml"] != "name: Mine\n" {
+		t.Error("modified workflow was replaced")
+	}
+
+	manifest, err := gen.LoadManifest(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := manifest.Project.AddOns; len(got) != 1 || got[0] != "docker" {
+		t.Errorf("recorded add-ons = %v, want [docker]", got)
+	}
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackupDir holds one directory per run, containing the original content of
+// every file that run replaced plus a run.json describing what it changed.
+const BackupDir = ".claude/.cc-backups"
+
+const runFile = "run.json"
+
+// Run describes the changes made by a single cc run. Paths are relative to the
+// project root.
+type Run struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Created  []string  `json:"created,omitempty"`
+	Replaced []string  `json:"replaced,omitempty"`
+	Dirs     []string  `json:"dirs,omitempty"`
+}
+
+func newRun(fsys FS, root string, now time.Time) *Run {
+	now = now.UTC()
+	id := now.Format("20060102T150405Z")
+
+	// Two runs within the same second must not share a backup directory
+	for i := 1; ; i++ {
+		if _, err := fsys.Stat(filepath.Join(root, BackupDir, id)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format("20060102T150405Z"), i)
+	}
+
+	return &Run{ID: id, Time: now}
+}
+
+func (g *Generator) runDir() string {
+	return filepath.Join(g.root, BackupDir, g.run.ID)
+}
+
+// backupFile copies the current content of path into this run's backup
+// directory before it is replaced.
+func (g *Generator) backupFile(path string) error {
+	rel, err := filepath.Rel(g.root, path)
+	if err != nil {
+		return err
+	}
+
+	content, err := g.FS.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return g.put(filepath.Join(g.runDir(), rel), content)
+}
+
+// saveRun writes run.json for the current run, derived from the journal. Runs
+// that changed nothing are not recorded.
+func (g *Generator) saveRun() error {
+	for _, entry := range g.journal.entries {
+		rel, err := filepath.Rel(g.root, entry.path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if isBackupPath(rel) {
+			continue
+		}
+
+		switch {
+		case entry.dir:
+			g.run.Dirs = append(g.run.Dirs, rel)
+		case entry.created:
+			g.run.Created = append(g.run.Created, rel)
+		default:
+			g.run.Replaced = append(g.run.Replaced, rel)
+		}
+	}
+
+	if len(g.run.Created) == 0 && len(g.run.Replaced) == 0 {
+		return nil
+	}
+
+	// Backups must never be committed, whatever the project's .gitignore says
+	ignorePath := filepath.Join(g.root, BackupDir, ".gitignore")
+	if _, err := g.FS.Stat(ignorePath); errors.Is(err, fs.ErrNotExist) {
+		if err := g.put(ignorePath, []byte("*\n")); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(g.run, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return g.put(filepath.Join(g.runDir(), runFile), append(data, '\n'))
+}
+
+func isBackupPath(rel string) bool {
+	return rel == BackupDir || strings.HasPrefix(rel, BackupDir+"/")
+}
+
+// ListRuns returns the recorded runs under root, newest first.
+func (g *Generator) ListRuns(root string) ([]Run, error) {
+	entries, err := g.FS.ReadDir(filepath.Join(root, BackupDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []Run
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		data, err := g.FS.ReadFile(filepath.Join(root, BackupDir, entry.Name(), runFile))
+		if err != nil {
+			continue
+		}
+
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("failed to parse run %s: %w", entry.Name(), err)
+		}
+		runs = append(runs, run)
+	}
+
+	// IDs compare as strings, which puts -10 before -9 within one second
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].Time.Equal(runs[j].Time) {
+			return runs[i].Time.After(runs[j].Time)
+		}
+		return runSequence(runs[i].ID) > runSequence(runs[j].ID)
+	})
+
+	return runs, nil
+}
+
+// runSequence returns the counter newRun appends to the IDs of runs made
+// within the same second, or 0 for the first.
+func runSequence(id string) int {
+	_, suffix, found := strings.Cut(id, "Z-")
+	if !found {
+		return 0
+	}
+	n, _ := strconv.Atoi(suffix)
+	return n
+}
+
+// Undo reverts the most recent run under root: created files are removed,
+// replaced files are restored from backup and the run's backups are deleted.
+// Undo is itself journaled, so a failure leaves the tree untouched.
+func (g *Generator) Undo(root string) (run *Run, err error) {
+	runs, err := g.ListRuns(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, errors.New("no runs to undo")
+	}
+
+	g.journal = newJournal(g.FS)
+	g.root = root
+	g.run = &runs[0]
+	defer func() {
+		if err != nil {
+			if rbErr := g.journal.rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	for _, rel := range g.run.Replaced {
+		content, err := g.FS.ReadFile(filepath.Join(g.runDir(), filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup of %s: %w", rel, err)
+		}
+		if err := g.put(filepath.Join(root, filepath.FromSlash(rel)), content); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+	}
+
+	for _, rel := range g.run.Created {
+		if err := g.remove(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+	}
+
+	if err := g.removeTree(g.runDir()); err != nil {
+		return nil, fmt.Errorf("failed to remove backups: %w", err)
+	}
+	if len(runs) == 1 {
+		if err := g.removeTree(filepath.Join(root, BackupDir)); err != nil {
+			return nil, fmt.Errorf("failed to remove backups: %w", err)
+		}
+	}
+
+	for i := len(g.run.Dirs) - 1; i >= 0; i-- {
+		if err := g.removeEmptyDir(filepath.Join(root, filepath.FromSlash(g.run.Dirs[i]))); err != nil {
+			return nil, err
+		}
+	}
+
+	return g.run, nil
+}
+
+// remove deletes a file, journaling its content first.
+func (g *Generator) remove(path string) error {
+	if _, err := g.FS.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := g.journal.recordFile(path); err != nil {
+		return err
+	}
+	return g.FS.Remove(path)
+}
+
+// removeEmptyDir deletes path only if it is an empty directory. Directories the
+// user has since put files into are kept.
+func (g *Generator) removeEmptyDir(path string) error {
+	entries, err := g.FS.ReadDir(path)
+	if errors.Is(err, fs.ErrNotExist) || len(entries) > 0 {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	g.journal.recordDirRemoval(path)
+	return g.FS.Remove(path)
+}
+
+// removeTree deletes a directory tree file by file so that every removal is
+// journaled.
+func (g *Generator) removeTree(path string) error {
+	entries, err := g.FS.ReadDir(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		if entry.IsDir() {
+			if err := g.removeTree(child); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := g.remove(child); err != nil {
+			return err
+		}
+	}
+
+	return g.removeEmptyDir(path)
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"strings"
+)
+
+// Files that projects usually already have, like .gitignore and Makefile, are
+// merged rather than replaced: cc owns only the lines between these markers.
+const (
+	blockBegin = "# >>> cc managed >>>"
+	blockEnd   = "# <<< cc managed <<<"
+)
+
+func wrapBlock(block string) string {
+	return blockBegin + "\n" + strings.TrimRight(block, "\n") + "\n" + blockEnd + "\n"
+}
+
+// extractBlock returns the content between the markers, if content has a block.
+func extractBlock(content string) (string, bool) {
+	start := strings.Index(content, blockBegin+"\n")
+	if start < 0 {
+		return "", false
+	}
+	inner := content[start+len(blockBegin)+1:]
+
+	end := strings.Index(inner, blockEnd)
+	if end < 0 {
+		return "", false
+	}
+
+	return inner[:end], true
+}
+
+// mergeBlock replaces the managed block in content, or appends one if there
+// is none yet.
+func mergeBlock(content, block string) string {
+	if content == "" {
+		return wrapBlock(block)
+	}
+
+	if before, after, ok := splitBlock(content); ok {
+		return before + wrapBlock(block) + after
+	}
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + wrapBlock(block)
+}
+
+// stripBlock removes the managed block from content, along with the blank
+// separator line mergeBlock adds in front of it.
+func stripBlock(content string) string {
+	before, after, ok := splitBlock(content)
+	if !ok {
+		return content
+	}
+
+	if after == "" {
+		before = strings.TrimSuffix(before, "\n")
+	}
+
+	return before + after
+}
+
+func splitBlock(content string) (before, after string, ok bool) {
+	start := strings.Index(content, blockBegin+"\n")
+	if start < 0 {
+		return "", "", false
+	}
+
+	rest := content[start:]
+	end := strings.Index(rest, blockEnd)
+	if end < 0 {
+		return "", "", false
+	}
+	end += len(blockEnd)
+	if end < len(rest) && rest[end] == '\n' {
+		end++
+	}
+
+	return content[:start], rest[end:], true
+}
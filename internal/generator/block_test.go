@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"testing"
+)
+
+func TestMergeAndStripBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+	}{
+		{name: "empty", existing: ""},
+		{name: "content", existing: "/bin/\n"},
+		{name: "no trailing newline", existing: "/bin/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeBlock(tt.existing, "a\nb\n")
+
+			inner, ok := extractBlock(merged)
+			if !ok || inner != "a\nb\n" {
+				t.Fatalf("extractBlock(%q) = %q, %v", merged, inner, ok)
+			}
+
+			if again := mergeBlock(merged, "a\nb\n"); again != merged {
+				t.Errorf("merging twice changed the file:\n%q\n%q", merged, again)
+			}
+
+			want := tt.existing
+			if want != "" && want[len(want)-1] != '\n' {
+				want += "\n"
+			}
+			if got := stripBlock(merged); got != want {
+				t.Errorf("stripBlock() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestDefinedMakeTargets(t *testing.T) {
+	content := `VERSION := 1.0
+.PHONY: test build
+all: build
+
+test build:
+	go test ./...
+deploy::
+	@echo "target: not a rule"
+`
+	got := definedMakeTargets(content)
+
+	for _, name := range []string{"all", "test", "build", "deploy"} {
+		if !got[name] {
+			t.Errorf("target %q not found in %v", name, got)
+		}
+	}
+	for _, name := range []string{"VERSION", ".PHONY", "@echo"} {
+		if got[name] {
+			t.Errorf("%q reported as a target", name)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MatrixAxis is one dimension of the CI build matrix. The os axis sets the
+// runner, and an axis named after a detected stack (go, node, python or
+// terraform) sets the version its setup step installs.
+type MatrixAxis struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// uvVersion is the uv release CI installs for uv projects.
+const uvVersion = "0.7.13"
+
+var matrixNameRe = regexp.MustCompile(`^[A-Za-z][\w-]*$`)
+
+// Stacks cc knows how to set up in CI, in the order their steps are written.
+const (
+	StackGo         = "go"
+	StackNode       = "node"
+	StackPython     = "python"
+	StackUV         = "uv"
+	StackTerraform  = "terraform"
+	StackKubernetes = "kubernetes"
+	StackDagger     = "dagger"
+)
+
+// detectStacks returns the languages and tools the project uses, judged by
+// the files in its root and the project type.
+func (g *Generator) detectStacks(projectPath string) ([]string, error) {
+	markers := []struct {
+		stack string
+		files []string
+	}{
+		{StackGo, []string{"go.mod"}},
+		{StackNode, []string{"package.json"}},
+		{StackPython, []string{"pyproject.toml", "requirements.txt", "setup.py"}},
+		{StackUV, []string{"uv.lock"}},
+		{StackTerraform, []string{"main.tf", "versions.tf", ".terraform.lock.hcl"}},
+		{StackKubernetes, []string{"k8s/base/kustomization.yaml"}},
+		{StackDagger, []string{"dagger.json"}},
+	}
+
+	var stacks []string
+	for _, marker := range markers {
+		if contains(g.ptype.Stacks, marker.stack) {
+			stacks = append(stacks, marker.stack)
+			continue
+		}
+		for _, file := range marker.files {
+			if _, exists, err := g.readCurrent(filepath.Join(projectPath, file)); err != nil {
+				return nil, err
+			} else if exists {
+				stacks = append(stacks, marker.stack)
+				break
+			}
+		}
+	}
+
+	return stacks, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Generator) exists(path string) bool {
+	_, exists, err := g.readCurrent(path)
+	return err == nil && exists
+}
+
+func (g *Generator) generateGenericGitHubWorkflow(projectPath string, config *ProjectConfig) error {
+	workflowDir := filepath.Join(projectPath, ".github", "workflows")
+
+	stacks, err := g.detectStacks(projectPath)
+	if err != nil {
+		return err
+	}
+
+	matrix := make(map[string]bool)
+	for _, axis := range config.CIMatrix {
+		if !matrixNameRe.MatchString(axis.Name) || len(axis.Values) == 0 {
+			return fmt.Errorf("invalid CI matrix axis %q", axis.Name)
+		}
+		if matrix[axis.Name] {
+			return fmt.Errorf("duplicate CI matrix axis %q", axis.Name)
+		}
+		matrix[axis.Name] = true
+	}
+
+	// version returns the matrix expression for a stack's version, or def
+	version := func(stack, def string) string {
+		if matrix[stack] {
+			return "${{ matrix." + stack + " }}"
+		}
+		return def
+	}
+
+	var b strings.Builder
+	b.WriteString(`name: CI
+
+on:
+  push:
+    branches: [main]
+  pull_request:
+    branches: [main]
+
+permissions:
+  contents: read
+
+jobs:
+  test:
+`)
+
+	if len(config.CIMatrix) > 0 {
+		b.WriteString("    strategy:\n      fail-fast: false\n      matrix:\n")
+		for _, axis := range config.CIMatrix {
+			quoted := make([]string, len(axis.Values))
+			for i, value := range axis.Values {
+				quoted[i] = fmt.Sprintf("%q", value)
+			}
+			fmt.Fprintf(&b, "        %s: [%s]\n", axis.Name, strings.Join(quoted, ", "))
+		}
+	}
+	fmt.Fprintf(&b, "    runs-on: %s\n", version("os", "ubuntu-latest"))
+
+	// A Dagger pipeline brings its own toolchains, so CI only needs the
+	// dagger CLI
+	if contains(stacks, StackDagger) {
+		b.WriteString(`    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          persist-credentials: false
+
+      - name: Install Dagger
+        run: |
+          mkdir -p "$HOME/.local/bin"
+          curl -fsSL https://dl.dagger.io/dagger/install.sh | BIN_DIR="$HOME/.local/bin" DAGGER_VERSION=` + daggerVersion + ` sh
+          echo "$HOME/.local/bin" >> "$GITHUB_PATH"
+
+      - name: Run tests
+        run: dagger call test
+
+      - name: Run linting
+        run: dagger call lint
+
+      - name: Build project
+        run: dagger call build
+`)
+		b.WriteString(g.ptype.CIJobs)
+		return g.writeFile(filepath.Join(workflowDir, "ci.yml"), b.String())
+	}
+
+	for _, stack := range stacks {
+		if stack == StackTerraform {
+			b.WriteString("    env:\n      TF_PLUGIN_CACHE_DIR: ${{ github.workspace }}/.terraform.d/plugin-cache\n")
+		}
+	}
+
+	b.WriteString(`    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          persist-credentials: false
+`)
+
+	for _, stack := range stacks {
+		b.WriteString("\n")
+		switch stack {
+		case StackGo:
+			b.WriteString("      - uses: " + uses("actions/setup-go") + "\n        with:\n")
+			if matrix[StackGo] {
+				fmt.Fprintf(&b, "          go-version: %s\n", version(StackGo, ""))
+			} else {
+				b.WriteString("          go-version-file: go.mod\n")
+			}
+			b.WriteString("          cache: true\n")
+
+		case StackNode:
+			b.WriteString("      - uses: " + uses("actions/setup-node") + "\n        with:\n")
+			if !matrix[StackNode] && g.exists(filepath.Join(projectPath, ".nvmrc")) {
+				b.WriteString("          node-version-file: .nvmrc\n")
+			} else {
+				fmt.Fprintf(&b, "          node-version: %s\n", version(StackNode, `"22"`))
+			}
+			// setup-node fails to cache without a lockfile
+			switch {
+			case g.exists(filepath.Join(projectPath, "package-lock.json")):
+				b.WriteString("          cache: npm\n")
+			case g.exists(filepath.Join(projectPath, "yarn.lock")):
+				b.WriteString("          cache: yarn\n")
+			}
+
+		case StackPython:
+			b.WriteString("      - uses: " + uses("actions/setup-python") + "\n        with:\n")
+			fmt.Fprintf(&b, "          python-version: %s\n", version(StackPython, `"3.12"`))
+			// uv keeps its own cache, set up in the uv step
+			if contains(stacks, StackUV) {
+				break
+			}
+			b.WriteString("          cache: pip\n")
+			if !g.exists(filepath.Join(projectPath, "requirements.txt")) {
+				if g.exists(filepath.Join(projectPath, "pyproject.toml")) {
+					b.WriteString("          cache-dependency-path: pyproject.toml\n")
+				} else {
+					b.WriteString("          cache-dependency-path: setup.py\n")
+				}
+			}
+
+		case StackUV:
+			b.WriteString(`      - name: Install uv
+        run: pipx install uv==` + uvVersion + `
+
+      - name: Cache uv
+        uses: ` + uses("actions/cache") + `
+        with:
+          path: ~/.cache/uv
+          key: uv-${{ runner.os }}-${{ hashFiles('uv.lock', 'pyproject.toml') }}
+`)
+
+		case StackTerraform:
+			b.WriteString("      - uses: " + uses("hashicorp/setup-terraform") + "\n")
+			if matrix[StackTerraform] {
+				fmt.Fprintf(&b, "        with:\n          terraform_version: %s\n", version(StackTerraform, ""))
+			}
+			b.WriteString(`
+      - name: Cache Terraform providers
+        uses: ` + uses("actions/cache") + `
+        with:
+          path: .terraform.d/plugin-cache
+          key: terraform-${{ runner.os }}-${{ hashFiles('**/.terraform.lock.hcl') }}
+
+      - name: Create provider cache directory
+        run: mkdir -p .terraform.d/plugin-cache
+`)
+
+		case StackKubernetes:
+			// kubectl and helm come with the runner image
+			b.WriteString(`      - name: Install kubeconform
+        run: |
+          mkdir -p "$HOME/.local/bin"
+          curl -fsSL https://github.com/yannh/kubeconform/releases/download/` + kubeconformVersion + `/kubeconform-linux-amd64.tar.gz | tar -xz -C "$HOME/.local/bin" kubeconform
+          echo "$HOME/.local/bin" >> "$GITHUB_PATH"
+`)
+		}
+	}
+
+	b.WriteString(`
+      - name: Run tests
+        run: make test
+
+      - name: Run linting
+        run: make lint
+
+      - name: Build project
+        run: make build
+`)
+	b.WriteString(g.ptype.CIJobs)
+
+	return g.writeFile(filepath.Join(workflowDir, "ci.yml"), b.String())
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"path/filepath"
+)
+
+// ClaudeSecret is the repository secret the Claude workflows read the
+// Anthropic API key from.
+const ClaudeSecret = "ANTHROPIC_API_KEY"
+
+// generateClaudeActions adds workflows that run Claude on pull requests and
+// when someone mentions @claude. The API key only ever comes from a
+// repository secret.
+func (g *Generator) generateClaudeActions(projectPath string, config *ProjectConfig) error {
+	workflowDir := filepath.Join(projectPath, ".github", "workflows")
+
+	review := `name: Claude Review
+
+on:
+  pull_request:
+    types: [opened, synchronize, ready_for_review, reopened]
+
+permissions:
+  contents: read
+  pull-requests: write
+  issues: read
+  id-token: write
+
+# Only review the latest push to a pull request
+concurrency:
+  group: claude-review-${{ github.event.pull_request.number }}
+  cancel-in-progress: true
+
+jobs:
+  review:
+    # Secrets are not available to pull requests from forks
+    if: github.event.pull_request.draft == false && github.event.pull_request.head.repo.full_name == github.repository
+    runs-on: ubuntu-latest
+    timeout-minutes: 20
+    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          fetch-depth: 1
+
+      - name: Review with Claude
+        uses: ` + uses("anthropics/claude-code-action") + `
+        with:
+          anthropic_api_key: ${{ secrets.` + ClaudeSecret + ` }}
+          prompt: |
+            Review this pull request. Follow the conventions in CLAUDE.md.
+            Focus on correctness, security, test coverage and readability,
+            and leave inline comments for specific issues. Be concise.
+`
+
+	if err := g.writeFile(filepath.Join(workflowDir, "claude-review.yml"), review); err != nil {
+		return err
+	}
+
+	mention := `name: Claude
+
+on:
+  issue_comment:
+    types: [created]
+  pull_request_review_comment:
+    types: [created]
+  pull_request_review:
+    types: [submitted]
+  issues:
+    types: [opened, assigned]
+
+permissions:
+  contents: write
+  pull-requests: write
+  issues: write
+  id-token: write
+
+# One run per issue or pull request at a time; queued mentions wait
+concurrency:
+  group: claude-${{ github.event.issue.number || github.event.pull_request.number }}
+  cancel-in-progress: false
+
+jobs:
+  claude:
+    # Only respond to @claude from people with write access to the repository
+    if: |
+      (
+        (github.event_name == 'issue_comment' && contains(github.event.comment.body, '@claude') && contains(fromJSON('["OWNER","MEMBER","COLLABORATOR"]'), github.event.comment.author_association)) ||
+        (github.event_name == 'pull_request_review_comment' && contains(github.event.comment.body, '@claude') && contains(fromJSON('["OWNER","MEMBER","COLLABORATOR"]'), github.event.comment.author_association)) ||
+        (github.event_name == 'pull_request_review' && contains(github.event.review.body, '@claude') && contains(fromJSON('["OWNER","MEMBER","COLLABORATOR"]'), github.event.review.author_association)) ||
+        (github.event_name == 'issues' && (contains(github.event.issue.body, '@claude') || contains(github.event.issue.title, '@claude')) && contains(fromJSON('["OWNER","MEMBER","COLLABORATOR"]'), github.event.issue.author_association))
+      )
+    runs-on: ubuntu-latest
+    timeout-minutes: 30
+    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          fetch-depth: 1
+
+      - name: Run Claude
+        uses: ` + uses("anthropics/claude-code-action") + `
+        with:
+          anthropic_api_key: ${{ secrets.` + ClaudeSecret + ` }}
+`
+
+	return g.writeFile(filepath.Join(workflowDir, "claude.yml"), mention)
+}
@@ -0,0 +1,239 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnerRule assigns the files matching Pattern to Owners (@user,
+// @org/team or an email address). Patterns use CODEOWNERS (gitignore) syntax.
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+}
+
+func (r CodeOwnerRule) String() string {
+	return r.Pattern + " " + strings.Join(r.Owners, " ")
+}
+
+// codeOwnersPaths are the locations GitHub and GitLab read CODEOWNERS from.
+var codeOwnersPaths = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+var codeOwnerRe = regexp.MustCompile(`^(@[\w.-]+(/[\w.-]+)?|[^@\s]+@[^@\s]+\.[^@\s]+)$`)
+
+// generateCodeOwners writes the configured rules, or a rule making the GitHub
+// owner responsible for everything, into CODEOWNERS. Rules already in the file
+// are not repeated.
+func (g *Generator) generateCodeOwners(projectPath string, config *ProjectConfig) error {
+	rules := config.CodeOwners
+	catchAll := len(rules) == 0
+	if catchAll {
+		if config.GitHubUsername == "" {
+			return nil
+		}
+		rules = []CodeOwnerRule{{Pattern: "*", Owners: []string{"@" + config.GitHubUsername}}}
+	}
+
+	if err := g.validateCodeOwners(projectPath, rules); err != nil {
+		return err
+	}
+
+	path, err := g.codeOwnersPath(projectPath)
+	if err != nil {
+		return err
+	}
+
+	defined := map[string]bool{}
+	if !config.Overwrite {
+		existing, _, err := g.readDisk(path)
+		if err != nil {
+			return err
+		}
+		defined = definedCodeOwnerRules(stripBlock(existing))
+	}
+	// The block goes after the existing rules, and the last matching rule
+	// wins, so a catch-all there would take every file away from them
+	if catchAll && len(defined) > 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by cc - Claude Code optimization tool\n")
+	b.WriteString("# Later rules take precedence over earlier ones.\n")
+	added := 0
+	for _, rule := range rules {
+		line := rule.String()
+		if defined[line] {
+			continue
+		}
+		defined[line] = true
+		b.WriteString(line + "\n")
+		added++
+	}
+	if added == 0 {
+		return g.skipFile(path)
+	}
+
+	return g.writeBlock(path, b.String())
+}
+
+// codeOwnersPath returns the existing CODEOWNERS file, or where a new one
+// should go: .gitlab/ for GitLab projects and .github/ otherwise.
+func (g *Generator) codeOwnersPath(projectPath string) (string, error) {
+	for _, candidate := range codeOwnersPaths {
+		path := filepath.Join(projectPath, filepath.FromSlash(candidate))
+		if _, exists, err := g.readCurrent(path); err != nil {
+			return "", err
+		} else if exists {
+			return path, nil
+		}
+	}
+
+	gitlab := false
+	if remote, ok := g.DetectRemote(projectPath); ok && strings.Contains(remote.Host, "gitlab") {
+		gitlab = true
+	} else if _, err := g.FS.Stat(filepath.Join(projectPath, ".gitlab-ci.yml")); err == nil {
+		gitlab = true
+	}
+
+	if gitlab {
+		return filepath.Join(projectPath, ".gitlab", "CODEOWNERS"), nil
+	}
+	return filepath.Join(projectPath, ".github", "CODEOWNERS"), nil
+}
+
+// definedCodeOwnerRules returns the rules in a CODEOWNERS file, normalized to
+// single spaces.
+func definedCodeOwnerRules(content string) map[string]bool {
+	defined := make(map[string]bool)
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		defined[strings.Join(fields, " ")] = true
+	}
+
+	return defined
+}
+
+// validateCodeOwners checks owner syntax and that every pattern matches at
+// least one file in the project or in the plan, to catch typos.
+func (g *Generator) validateCodeOwners(projectPath string, rules []CodeOwnerRule) error {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, rule := range rules {
+		if rule.Pattern == "" || strings.HasPrefix(rule.Pattern, "!") || strings.ContainsAny(rule.Pattern, " \t") {
+			return fmt.Errorf("invalid CODEOWNERS pattern %q", rule.Pattern)
+		}
+		if len(rule.Owners) == 0 {
+			return fmt.Errorf("CODEOWNERS pattern %q has no owners", rule.Pattern)
+		}
+		for _, owner := range rule.Owners {
+			if !codeOwnerRe.MatchString(owner) {
+				return fmt.Errorf("invalid CODEOWNERS owner %q for %q (want @user, @org/team or an email)", owner, rule.Pattern)
+			}
+		}
+		patterns[rule.Pattern] = codeOwnersPattern(rule.Pattern)
+	}
+
+	match := func(rel string) {
+		for pattern, re := range patterns {
+			if re.MatchString(rel) {
+				delete(patterns, pattern)
+			}
+		}
+	}
+
+	for _, file := range g.plan.Files {
+		match(file.Path)
+	}
+
+	errDone := errors.New("all patterns matched")
+	var walk func(dir, rel string) error
+	walk = func(dir, rel string) error {
+		entries, err := g.FS.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if len(patterns) == 0 {
+				return errDone
+			}
+			if entry.Name() == ".git" {
+				continue
+			}
+			path := entry.Name()
+			if rel != "" {
+				path = rel + "/" + path
+			}
+			if entry.IsDir() {
+				if err := walk(filepath.Join(dir, entry.Name()), path); err != nil {
+					return err
+				}
+				continue
+			}
+			match(path)
+		}
+		return nil
+	}
+	if err := walk(projectPath, ""); err != nil && !errors.Is(err, errDone) && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to list project files: %w", err)
+	}
+
+	for _, rule := range rules {
+		if _, ok := patterns[rule.Pattern]; ok {
+			return fmt.Errorf("CODEOWNERS pattern %q matches no files", rule.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// codeOwnersPattern compiles a CODEOWNERS pattern to a regular expression over
+// slash separated file paths. As in .gitignore, a pattern containing a slash
+// other than a trailing one is anchored to the project root.
+func codeOwnersPattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			b.WriteString("[^/]*")
+		case pattern[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(/.*)?$")
+	}
+
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestCodeOwnersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "cmd/root.go", true},
+		{"*.go", "cmd/root.go", true},
+		{"*.go", "README.md", false},
+		{"docs/", "docs/index.md", true},
+		{"docs/", "api/docs/index.md", true},
+		{"/docs/", "api/docs/index.md", false},
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/guides/setup.md", true},
+		{"docs/*", "api/docs/index.md", false},
+		{"**/logs", "build/logs/today.log", true},
+		{"apps/**/main.go", "apps/a/b/main.go", true},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+	}
+
+	for _, tt := range tests {
+		if got := codeOwnersPattern(tt.pattern).MatchString(tt.path); got != tt.want {
+			t.Errorf("pattern %q on %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeOwners(t *testing.T) {
+	existing := "# Team rules\n/docs/ @acme/writers\n*.go   @acme/go\n"
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		rules   []CodeOwnerRule
+		path    string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "merge without duplicates",
+			files: map[string]string{".github/CODEOWNERS": existing, "docs/index.md": "", "main.go": ""},
+			rules: []CodeOwnerRule{
+				{Pattern: "*.go", Owners: []string{"@acme/go"}},
+				{Pattern: "/docs/", Owners: []string{"@acme/writers"}},
+				{Pattern: "Makefile", Owners: []string{"@acme/platform"}},
+				{Pattern: "Makefile", Owners: []string{"@acme/platform"}},
+			},
+			path: ".github/CODEOWNERS",
+			want: []string{"/docs/ @acme/writers", "*.go   @acme/go", "Makefile @acme/platform"},
+		},
+		{
+			name:  "existing root file",
+			files: map[string]string{"CODEOWNERS": existing, "docs/index.md": ""},
+			rules: []CodeOwnerRule{{Pattern: "CLAUDE.md", Owners: []string{"dev@example.com"}}},
+			path:  "CODEOWNERS",
+			want:  []string{"CLAUDE.md dev@example.com"},
+		},
+		{
+			name:  "gitlab",
+			files: map[string]string{".gitlab-ci.yml": "", "main.go": ""},
+			rules: []CodeOwnerRule{{Pattern: "*.go", Owners: []string{"@acme/go"}}},
+			path:  ".gitlab/CODEOWNERS",
+			want:  []string{"*.go @acme/go"},
+		},
+		{
+			name:    "pattern matches nothing",
+			rules:   []CodeOwnerRule{{Pattern: "/srv/", Owners: []string{"@acme"}}},
+			wantErr: `"/srv/" matches no files`,
+		},
+		{
+			name:    "bad owner",
+			rules:   []CodeOwnerRule{{Pattern: "*", Owners: []string{"acme"}}},
+			wantErr: `invalid CODEOWNERS owner "acme"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := newTestGenerator(AferoFS{Fs: newMemFS(t, tt.files)})
+
+			plan, err := gen.Plan(t.Context(), &ProjectConfig{Root: testRoot, Name: "example", CodeOwners: tt.rules})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Plan() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var content string
+			for _, file := range plan.Files {
+				if file.Path == tt.path {
+					content = string(file.Content)
+				}
+			}
+			for _, line := range tt.want {
+				if strings.Count(content, line+"\n") != 1 {
+					t.Errorf("%s does not contain %q exactly once:\n%s", tt.path, line, content)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultCodeOwnerKeepsExistingRules(t *testing.T) {
+	existing := "/docs/ @acme/docs-team\n"
+	mem := newMemFS(t, map[string]string{".github/CODEOWNERS": existing, "docs/index.md": ""})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example", GitHubUsername: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := afero.ReadFile(mem, filepath.Join(testRoot, ".github", "CODEOWNERS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != existing {
+		t.Errorf("CODEOWNERS = %q, want the existing rules untouched", content)
+	}
+}
@@ -0,0 +1,525 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvVar is an environment variable the project's code reads.
+type EnvVar struct {
+	Name string
+	// Default is the value the code falls back to, if it is a plain literal
+	Default string
+	// Optional is set when every use has a default, even one that could not
+	// be read
+	Optional    bool
+	Description string
+	Uses        []EnvUse
+}
+
+// EnvUse is where a variable is read. Path is relative to the project root
+// and slash separated.
+type EnvUse struct {
+	Path string
+	Line int
+}
+
+// EnvCheck compares the variables the code reads with .env.example.
+type EnvCheck struct {
+	// Example is false if the project has no .env.example
+	Example bool
+	// Missing are read by the code but not in .env.example
+	Missing []EnvVar
+	// Unused are in .env.example but not read by the code
+	Unused []string
+}
+
+// EnvExampleFile is the file generated from the variables the code reads.
+const EnvExampleFile = ".env.example"
+
+// envRead is a variable read found in one line of code.
+type envRead struct {
+	name        string
+	defaultVal  string
+	optional    bool
+	description string
+}
+
+// envPattern finds variable reads in one line. The first submatch is the
+// name and the second, if any, a default.
+type envPattern struct {
+	re         *regexp.Regexp
+	hasDefault bool
+}
+
+var envPatterns = map[string][]envPattern{
+	"go": {
+		{re: regexp.MustCompile(`\bos\.(?:Getenv|LookupEnv)\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`)},
+	},
+	"python": {
+		// Any second argument makes the variable optional, but only a plain
+		// literal, positional or default=, is taken as its default
+		{re: regexp.MustCompile(`\bos\.(?:environ\.get|getenv)\(\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*(?:,\s*(?:default\s*=\s*)?(?:(?:["']([^"']*)["']|([\w.-]+))\s*[,)])?)?`), hasDefault: true},
+		{re: regexp.MustCompile(`\bos\.environ\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`)},
+	},
+	"node": {
+		{re: regexp.MustCompile(`\bprocess\.env\.([A-Za-z_][A-Za-z0-9_]*)(?:\s*(?:\?\?|\|\|)\s*(?:["'` + "`" + `]([^"'` + "`" + `]*)["'` + "`" + `]|([\w.-]+)))?`), hasDefault: true},
+		{re: regexp.MustCompile(`\bprocess\.env\[\s*["']([A-Za-z_][A-Za-z0-9_]*)["']\s*\]`)},
+	},
+}
+
+// envSourceKinds maps source file extensions to the patterns that apply.
+var envSourceKinds = map[string]string{
+	".go":  "go",
+	".py":  "python",
+	".js":  "node",
+	".mjs": "node",
+	".cjs": "node",
+	".jsx": "node",
+	".ts":  "node",
+	".mts": "node",
+	".cts": "node",
+	".tsx": "node",
+	".tf":  "terraform",
+}
+
+// envSystemVars are set by the shell or CI rather than by the project, so they
+// do not belong in .env.example.
+var envSystemVars = map[string]bool{
+	"HOME": true, "PATH": true, "USER": true, "SHELL": true, "PWD": true,
+	"TMPDIR": true, "TMP": true, "TEMP": true, "TERM": true, "LANG": true,
+	"LC_ALL": true, "CI": true, "GITHUB_ACTIONS": true,
+}
+
+var (
+	settingsClassRe  = regexp.MustCompile(`^(\s*)class\s+\w+\s*\(\s*(?:[\w.]+\.)?BaseSettings\s*\)\s*:`)
+	settingsFieldRe  = regexp.MustCompile(`^(\w+)\s*:\s*[^=]+?(?:=\s*(.+))?$`)
+	envPrefixRe      = regexp.MustCompile(`\benv_prefix\s*=\s*["']([^"']*)["']`)
+	tfVariableRe     = regexp.MustCompile(`^variable\s+"([A-Za-z_][\w-]*)"\s*\{`)
+	tfDescriptionRe  = regexp.MustCompile(`^description\s*=\s*"(.*)"\s*$`)
+	tfDefaultRe      = regexp.MustCompile(`^default\s*=\s*(.+)$`)
+	envAssignmentRe  = regexp.MustCompile(`^(?:#\s*)?(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)=`)
+	pythonCommentRe  = regexp.MustCompile(`\s+#.*$`)
+	pythonLiteralRe  = regexp.MustCompile(`^(?:"([^"]*)"|'([^']*)'|(-?[\d.]+|True|False))$`)
+	secretVariableRe = regexp.MustCompile(`(?i)secret|token|passw(?:or)?d|api_?key|private_?key|credential`)
+)
+
+// isEnvTestFile reports whether rel is a test, whose variables configure the
+// tests rather than the project.
+func isEnvTestFile(rel string) bool {
+	name := path.Base(rel)
+	for _, pattern := range []string{"*_test.go", "test_*.py", "*_test.py", "conftest.py", "*.test.*", "*.spec.*"} {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// envReads returns the variables one source file reads, by line number.
+func envReads(rel, content string) map[int][]envRead {
+	kind := envSourceKinds[path.Ext(rel)]
+	if kind == "" || isEnvTestFile(rel) {
+		return nil
+	}
+
+	lines := strings.Split(content, "\n")
+	switch kind {
+	case "terraform":
+		// Only root modules read TF_VAR_*; child modules get their inputs
+		// from the module that calls them
+		if strings.HasPrefix(rel, "modules/") || strings.Contains(rel, "/modules/") {
+			return nil
+		}
+		return terraformVariables(lines)
+	case "python":
+		reads := patternReads(lines, envPatterns[kind])
+		for line, read := range settingsFields(lines) {
+			reads[line] = append(reads[line], read...)
+		}
+		return reads
+	default:
+		return patternReads(lines, envPatterns[kind])
+	}
+}
+
+func patternReads(lines []string, patterns []envPattern) map[int][]envRead {
+	reads := make(map[int][]envRead)
+	for i, line := range lines {
+		for _, pattern := range patterns {
+			for _, match := range pattern.re.FindAllStringSubmatch(line, -1) {
+				read := envRead{name: match[1]}
+				if pattern.hasDefault && (match[2] != "" || match[3] != "" || strings.Contains(match[0], ",")) {
+					read.optional = true
+					read.defaultVal = match[2]
+					if match[3] != "" && match[3] != "None" && match[3] != "undefined" && match[3] != "null" {
+						read.defaultVal = match[3]
+					}
+				}
+				reads[i+1] = append(reads[i+1], read)
+			}
+		}
+	}
+	return reads
+}
+
+// settingsFields reads the fields of pydantic BaseSettings classes, which are
+// set from variables named after them with the class's env_prefix.
+func settingsFields(lines []string) map[int][]envRead {
+	reads := make(map[int][]envRead)
+	for i := 0; i < len(lines); i++ {
+		match := settingsClassRe.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+
+		// The class body is every following line indented deeper than the
+		// class, and fields sit at the indentation of its first line
+		classIndent := len(match[1])
+		var body []int
+		fieldIndent := -1
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if trimmed == "" {
+				continue
+			}
+			indent := len(lines[j]) - len(strings.TrimLeft(lines[j], " \t"))
+			if indent <= classIndent {
+				break
+			}
+			if fieldIndent < 0 {
+				fieldIndent = indent
+			}
+			body = append(body, j)
+		}
+
+		var prefix string
+		for _, j := range body {
+			if m := envPrefixRe.FindStringSubmatch(lines[j]); m != nil {
+				prefix = m[1]
+			}
+		}
+
+		inDocstring := false
+		for _, j := range body {
+			trimmed := strings.TrimSpace(lines[j])
+			if strings.Count(trimmed, `"""`)%2 == 1 {
+				inDocstring = !inDocstring
+				continue
+			}
+			indent := len(lines[j]) - len(strings.TrimLeft(lines[j], " \t"))
+			if inDocstring || indent != fieldIndent {
+				continue
+			}
+			m := settingsFieldRe.FindStringSubmatch(trimmed)
+			if m == nil || m[1] == "model_config" || strings.HasPrefix(m[1], "_") || strings.Contains(trimmed, "ClassVar") {
+				continue
+			}
+			read := envRead{name: strings.ToUpper(prefix + m[1])}
+			if m[2] != "" {
+				read.optional = true
+				read.defaultVal = pythonLiteral(strings.TrimSpace(pythonCommentRe.ReplaceAllString(m[2], "")))
+			}
+			reads[j+1] = append(reads[j+1], read)
+		}
+	}
+	return reads
+}
+
+// pythonLiteral returns a Python literal as it would be written in an
+// environment file, or "" if it is not a plain string, number or bool.
+func pythonLiteral(value string) string {
+	m := pythonLiteralRe.FindStringSubmatch(value)
+	if m == nil {
+		return ""
+	}
+	switch literal := m[1] + m[2] + m[3]; literal {
+	case "True", "False":
+		return strings.ToLower(literal)
+	default:
+		return literal
+	}
+}
+
+// terraformVariables reads the variable blocks of a Terraform file, which
+// are set from TF_VAR_<name>.
+func terraformVariables(lines []string) map[int][]envRead {
+	reads := make(map[int][]envRead)
+	for i := 0; i < len(lines); i++ {
+		match := tfVariableRe.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if match == nil {
+			continue
+		}
+
+		read := envRead{name: "TF_VAR_" + match[1]}
+		depth := 0
+		for j := i; j < len(lines); j++ {
+			line := strings.TrimSpace(lines[j])
+			// Attributes of the variable itself are at depth 1, not in
+			// nested validation blocks
+			if depth == 1 {
+				if m := tfDescriptionRe.FindStringSubmatch(line); m != nil {
+					read.description, _ = strconv.Unquote(`"` + m[1] + `"`)
+				}
+				if m := tfDefaultRe.FindStringSubmatch(line); m != nil {
+					read.optional = true
+					if value, err := strconv.Unquote(m[1]); err == nil {
+						read.defaultVal = value
+					} else if !strings.ContainsAny(m[1], "[{") && m[1] != "null" {
+						read.defaultVal = m[1]
+					}
+				}
+			}
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			if depth <= 0 {
+				break
+			}
+		}
+		reads[i+1] = append(reads[i+1], read)
+	}
+	return reads
+}
+
+// envSkipDirs hold build output, whose bundled code reads variables the
+// project itself does not.
+var envSkipDirs = []string{"dist", "build", "out", ".next", "coverage"}
+
+// detectEnvVars returns the variables read by the source files at paths,
+// sorted by name. read returns a file's content, or false to skip it.
+func detectEnvVars(paths []string, read func(rel string) (string, bool, error)) ([]EnvVar, error) {
+	byName := make(map[string]*EnvVar)
+	for _, rel := range paths {
+		if envSourceKinds[path.Ext(rel)] == "" || inEnvSkipDir(rel) {
+			continue
+		}
+		content, ok, err := read(rel)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		reads := envReads(rel, content)
+		lines := make([]int, 0, len(reads))
+		for line := range reads {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		for _, line := range lines {
+			for _, read := range reads[line] {
+				if envSystemVars[read.name] {
+					continue
+				}
+				v, ok := byName[read.name]
+				if !ok {
+					v = &EnvVar{Name: read.name, Optional: true}
+					byName[read.name] = v
+				}
+				v.Optional = v.Optional && read.optional
+				if v.Default == "" {
+					v.Default = read.defaultVal
+				}
+				if v.Description == "" {
+					v.Description = read.description
+				}
+				v.Uses = append(v.Uses, EnvUse{Path: rel, Line: line})
+			}
+		}
+	}
+
+	vars := make([]EnvVar, 0, len(byName))
+	for _, v := range byName {
+		vars = append(vars, *v)
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+func inEnvSkipDir(rel string) bool {
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		if contains(envSkipDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// envFiles returns the distinct files a variable is read in.
+func envFiles(v EnvVar) []string {
+	var files []string
+	for _, use := range v.Uses {
+		files = appendUnique(files, use.Path)
+	}
+	return files
+}
+
+// envUsedIn describes where a variable is read, naming at most three files.
+func envUsedIn(v EnvVar, quote string) string {
+	files := envFiles(v)
+	more := ""
+	if len(files) > 3 {
+		more = fmt.Sprintf(" and %d more", len(files)-3)
+		files = files[:3]
+	}
+	return quote + strings.Join(files, quote+", "+quote) + quote + more
+}
+
+// envExample renders .env.example. Optional variables are commented out with
+// their default; required secrets get a placeholder to replace.
+func envExample(name string, vars []EnvVar) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `# Environment variables read by %s, found by cc in its code.
+# Copy this file to .env and fill in the values; .env is git-ignored.
+# Commented-out variables are optional and show their default.
+`, name)
+
+	for _, v := range vars {
+		b.WriteString("\n")
+		if v.Description != "" {
+			fmt.Fprintf(&b, "# %s\n", v.Description)
+		}
+		fmt.Fprintf(&b, "# Used in %s\n", envUsedIn(v, ""))
+		switch {
+		case v.Optional:
+			fmt.Fprintf(&b, "# %s=%s\n", v.Name, v.Default)
+		case v.Default != "":
+			fmt.Fprintf(&b, "%s=%s\n", v.Name, v.Default)
+		case secretVariableRe.MatchString(v.Name):
+			fmt.Fprintf(&b, "%s=change-me\n", v.Name)
+		default:
+			fmt.Fprintf(&b, "%s=\n", v.Name)
+		}
+	}
+
+	return b.String()
+}
+
+// envNotes is the CLAUDE.md section listing the variables the code reads.
+func envNotes(vars []EnvVar) string {
+	if len(vars) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`## Environment Variables
+
+The code reads these variables. ` + "`.env.example`" + ` documents them; copy it to ` + "`.env`" + `
+for local values and never commit ` + "`.env`" + `. When code reads a new variable, add it
+to ` + "`.env.example`" + ` and run ` + "`cc env check`" + `.
+
+| Variable | Default | Used in |
+|----------|---------|---------|
+`)
+	for _, v := range vars {
+		def := "none"
+		switch {
+		case v.Default != "":
+			def = "`" + v.Default + "`"
+		case v.Optional:
+			def = "set in code"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n", v.Name, def, envUsedIn(v, "`"))
+	}
+
+	return b.String()
+}
+
+// generateEnvExample plans .env.example from the variables the project's
+// code reads, including the code planned so far, and plans CLAUDE.md again so
+// that it lists them.
+func (g *Generator) generateEnvExample(ctx context.Context, projectPath string, config *ProjectConfig) error {
+	files, err := g.projectFiles(ctx, projectPath)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(files))
+	for _, rel := range files {
+		seen[rel] = true
+	}
+	for _, file := range g.plan.Files {
+		if file.Action != ActionSkipped && !seen[file.Path] {
+			files = append(files, file.Path)
+		}
+	}
+	sort.Strings(files)
+
+	vars, err := detectEnvVars(files, func(rel string) (string, bool, error) {
+		return g.readCurrent(filepath.Join(projectPath, filepath.FromSlash(rel)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detect environment variables: %w", err)
+	}
+	if len(vars) == 0 {
+		return nil
+	}
+	g.envVars = vars
+
+	if err := g.writeFile(filepath.Join(projectPath, EnvExampleFile), envExample(config.Name, vars)); err != nil {
+		return err
+	}
+	// A CLAUDE.md from a generator replaces ours and is not re-rendered
+	if g.generated["CLAUDE.md"] {
+		return nil
+	}
+	return g.generateClaudeMD(projectPath, config)
+}
+
+// envExampleNames returns the variables an environment file sets or, in
+// comments, documents, in order.
+func envExampleNames(content string) []string {
+	var names []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := envAssignmentRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			names = appendUnique(names, m[1])
+		}
+	}
+	return names
+}
+
+// CheckEnv compares the variables the code under root reads with the ones
+// .env.example documents.
+func (g *Generator) CheckEnv(ctx context.Context, root string) (*EnvCheck, error) {
+	files, err := g.projectFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := detectEnvVars(files, func(rel string) (string, bool, error) {
+		if err := ctx.Err(); err != nil {
+			return "", false, err
+		}
+		return g.readScannable(filepath.Join(root, filepath.FromSlash(rel)))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect environment variables: %w", err)
+	}
+
+	content, exists, err := g.readDisk(filepath.Join(root, EnvExampleFile))
+	if err != nil {
+		return nil, err
+	}
+
+	check := &EnvCheck{Example: exists}
+	documented := envExampleNames(content)
+	used := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		used[v.Name] = true
+		if !contains(documented, v.Name) {
+			check.Missing = append(check.Missing, v)
+		}
+	}
+	for _, name := range documented {
+		if !used[name] {
+			check.Unused = append(check.Unused, name)
+		}
+	}
+
+	return check, nil
+}
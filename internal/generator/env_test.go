@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestDetectEnvVars(t *testing.T) {
+	files := map[string]string{
+		"cmd/app/main.go": `package main
+
+func main() {
+	dsn := os.Getenv("DATABASE_URL")
+	key, ok := os.LookupEnv("STRIPE_API_KEY")
+	home := os.Getenv("HOME")
+}
+`,
+		"cmd/app/main_test.go": `os.Getenv("UPDATE_GOLDEN")`,
+		"app/config.py": `import os
+
+from pydantic_settings import BaseSettings, SettingsConfigDict
+
+
+class Settings(BaseSettings):
+    """Settings.
+
+    name: not a field
+    """
+
+    model_config = SettingsConfigDict(env_prefix="APP_")
+
+    name: str = "api"  # shown in logs
+    debug: bool = False
+    secret_key: str
+    tags: list[str] = Field(default_factory=list)
+
+    def url(self) -> str:
+        return "x"
+
+
+TIMEOUT = int(os.getenv("REQUEST_TIMEOUT", "30"))
+PORT = int(os.getenv("PORT", str(8000)))
+DB = os.environ.get("DB", default="x")
+REGION = os.environ["AWS_REGION"]
+`,
+		"web/src/api.ts":       "const base = process.env.API_BASE_URL ?? \"http://localhost:3000\";\nconst region = process.env[\"AWS_REGION\"];\n",
+		"web/dist/bundle.js":   "process.env.BUNDLED",
+		"main.tf":              "variable \"region\" {\n  description = \"AWS region.\"\n  default     = \"eu-west-1\"\n}\n\nvariable \"replicas\" {\n  type = number\n\n  validation {\n    condition = var.replicas > 0\n  }\n}\n",
+		"modules/db/inputs.tf": "variable \"name\" {}\n",
+	}
+	gen := newTestGenerator(AferoFS{Fs: newMemFS(t, files)})
+
+	paths, err := gen.projectFiles(context.Background(), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars, err := detectEnvVars(paths, func(rel string) (string, bool, error) {
+		return files[rel], true, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, v := range vars {
+		entry := v.Name
+		if v.Optional {
+			entry += "?" + v.Default
+		}
+		got = append(got, entry)
+	}
+	want := []string{
+		"API_BASE_URL?http://localhost:3000",
+		"APP_DEBUG?false",
+		"APP_NAME?api",
+		"APP_SECRET_KEY",
+		"APP_TAGS?",
+		"AWS_REGION",
+		"DATABASE_URL",
+		"DB?x",
+		"PORT?",
+		"REQUEST_TIMEOUT?30",
+		"STRIPE_API_KEY",
+		"TF_VAR_region?eu-west-1",
+		"TF_VAR_replicas",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("variables = %q, want %q", got, want)
+	}
+
+	for _, v := range vars {
+		switch v.Name {
+		case "AWS_REGION":
+			wantUses := []EnvUse{{Path: "app/config.py", Line: 26}, {Path: "web/src/api.ts", Line: 2}}
+			if !reflect.DeepEqual(v.Uses, wantUses) {
+				t.Errorf("AWS_REGION uses = %+v, want %+v", v.Uses, wantUses)
+			}
+		case "TF_VAR_region":
+			if v.Description != "AWS region." {
+				t.Errorf("TF_VAR_region description = %q", v.Description)
+			}
+		}
+	}
+}
+
+func TestEnvExample(t *testing.T) {
+	content := envExample("api", []EnvVar{
+		{Name: "DATABASE_URL", Uses: []EnvUse{{Path: "main.go", Line: 3}}},
+		{Name: "LOG_LEVEL", Optional: true, Default: "info", Uses: []EnvUse{{Path: "a.py"}, {Path: "b.py"}, {Path: "c.py"}, {Path: "d.py"}}},
+		{Name: "STRIPE_API_KEY", Uses: []EnvUse{{Path: "main.go", Line: 4}}},
+	})
+
+	for _, want := range []string{
+		"\n# Used in main.go\nDATABASE_URL=\n",
+		"\n# Used in a.py, b.py, c.py and 1 more\n# LOG_LEVEL=info\n",
+		"\nSTRIPE_API_KEY=change-me\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf(".env.example has no %q:\n%s", want, content)
+		}
+	}
+	if findings := scanContent(EnvExampleFile, content); len(findings) != 0 {
+		t.Errorf("placeholders look like secrets: %+v", findings)
+	}
+	if got := envExampleNames(content); !reflect.DeepEqual(got, []string{"DATABASE_URL", "LOG_LEVEL", "STRIPE_API_KEY"}) {
+		t.Errorf("names = %q", got)
+	}
+}
+
+func TestCheckEnv(t *testing.T) {
+	mem := newMemFS(t, map[string]string{
+		"main.go": "package main\n\nvar a, b = os.Getenv(\"A\"), os.Getenv(\"B\")\n",
+	})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	check, err := gen.CheckEnv(context.Background(), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.Example || len(check.Missing) != 2 {
+		t.Errorf("without .env.example: %+v", check)
+	}
+
+	if err := afero.WriteFile(mem, filepath.Join(testRoot, EnvExampleFile), []byte("A=1\n# C=3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check, err = gen.CheckEnv(context.Background(), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.Example || len(check.Missing) != 1 || check.Missing[0].Name != "B" || check.Missing[0].Uses[0].Line != 3 {
+		t.Errorf("missing = %+v, want B on line 3", check.Missing)
+	}
+	if !reflect.DeepEqual(check.Unused, []string{"C"}) {
+		t.Errorf("unused = %q, want C", check.Unused)
+	}
+}
+
+func TestEnvVarsKeepGeneratedClaudeMD(t *testing.T) {
+	mem := newMemFS(t, map[string]string{"main.go": "package main\n\nvar dsn = os.Getenv(\"DATABASE_URL\")\n"})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+	gen.RegisterGenerator(func(ctx context.Context, config *ProjectConfig) ([]File, error) {
+		return []File{{Path: "CLAUDE.md", Content: "# Ours\n"}}, nil
+	})
+
+	plan, err := gen.Plan(context.Background(), &ProjectConfig{Root: testRoot, Name: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var example bool
+	for _, file := range plan.Files {
+		switch file.Path {
+		case "CLAUDE.md":
+			if string(file.Content) != "# Ours\n" {
+				t.Errorf("CLAUDE.md = %q, want the generator's", file.Content)
+			}
+		case EnvExampleFile:
+			example = true
+		}
+	}
+	if !example {
+		t.Errorf("no %s planned", EnvExampleFile)
+	}
+}
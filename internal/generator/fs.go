@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+)
+
+// FS is the filesystem the generator reads from and writes to. Paths use the
+// host's separator and are interpreted relative to the working directory.
+// WriteFile must replace files atomically: readers either see the old content
+// or the new, never a partial write.
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Mkdir(name string, perm fs.FileMode) error
+	Remove(name string) error
+}
+
+// OSFS is the real filesystem.
+type OSFS struct{}
+
+func (OSFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+// WriteFile stages content in a temp file next to name and renames it into
+// place.
+func (OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".cc-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, perm); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	if err := os.Rename(tmpName, name); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+func (OSFS) Mkdir(name string, perm fs.FileMode) error {
+	return os.Mkdir(name, perm)
+}
+
+func (OSFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// AferoFS adapts an afero filesystem, such as afero.NewMemMapFs, to FS.
+type AferoFS struct {
+	Fs afero.Fs
+}
+
+func (a AferoFS) Stat(name string) (fs.FileInfo, error) {
+	return a.Fs.Stat(name)
+}
+
+func (a AferoFS) ReadFile(name string) ([]byte, error) {
+	return afero.ReadFile(a.Fs, name)
+}
+
+func (a AferoFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	infos, err := afero.ReadDir(a.Fs, name)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]fs.DirEntry, 0, len(infos))
+	for _, info := range infos {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+// WriteFile stages content in a temp file next to name and renames it into
+// place.
+func (a AferoFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	tmp, err := afero.TempFile(a.Fs, filepath.Dir(name), ".cc-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		a.Fs.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		a.Fs.Remove(tmpName)
+		return err
+	}
+	if err := a.Fs.Chmod(tmpName, perm); err != nil {
+		a.Fs.Remove(tmpName)
+		return err
+	}
+
+	if err := a.Fs.Rename(tmpName, name); err != nil {
+		a.Fs.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+func (a AferoFS) Mkdir(name string, perm fs.FileMode) error {
+	return a.Fs.Mkdir(name, perm)
+}
+
+func (a AferoFS) Remove(name string) error {
+	return a.Fs.Remove(name)
+}
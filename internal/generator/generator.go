@@ -1,29 +1,71 @@
 package generator
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 	"time"
 )
 
 type ProjectConfig struct {
+	Root           string // directory to generate into, defaults to "."
 	Name           string
 	Type           string
 	Description    string
-	GitHubUsername string
+	GitHub         bool   // GitHub integration, with the owner taken from the remote
+	GitHubUsername string // owner, defaults to the GitHub remote's; also enables GitHub integration
+	GitHubHost     string // defaults to the GitHub remote's host, or github.com
+	GitHubRepo     string // defaults to the GitHub remote's repository, or Name
+	Module         string // Go module path, defaults to the git remote's path
+	CodeOwners     []CodeOwnerRule
+	IssueTemplates string // IssueTemplatesForms (the default) or IssueTemplatesMarkdown
+	ClaudeActions  bool   // add Claude review and @claude workflows
+	Release        bool   // add changelog and release tooling
+	CIMatrix       []MatrixAxis
+	AddOns         []string // layered onto the project type, such as helm
+	DenySecrets    bool     // deny Claude reading .env and other credential files
 	Overwrite      bool
+	Refresh        bool // also replace generated files the user has not modified
 	DryRun         bool
 	Verbose        bool
 	Integration    bool // true if integrating into existing project
 }
 
 type Generator struct {
+	// Log receives human-oriented progress output
+	Log io.Writer
+	// FS is the filesystem projects are read from and written to
+	FS FS
+	// Now returns the current time, used for dates in generated files and
+	// run IDs
+	Now func() time.Time
+
+	journal *journal
+	config  *ProjectConfig
+	root    string
+	run     *Run
+	plan    *Plan
+	planned map[string]int
+
+	manifest        *Manifest
+	manifestChanged bool
+
+	types      map[string]ProjectType
+	generators []FileGenerator
+	ptype      ProjectType // type of the project being planned, with add-ons
+	addOns     []AddOn
+	workspace  *Workspace      // packages of a monorepo, nil for a single project
+	envVars    []EnvVar        // variables the code reads, known once every file is planned
+	generated  map[string]bool // paths planned by type and registered generators
 }
 
 func New() *Generator {
-	return &Generator{}
+	return &Generator{Log: os.Stdout, FS: OSFS{}, Now: time.Now}
 }
 
 // GenerateProject is deprecated - use InitializeProject instead
@@ -31,29 +73,76 @@ func (g *Generator) GenerateProject(config *ProjectConfig) error {
 	return g.InitializeProject(config)
 }
 
+// InitializeProject plans and applies the Claude Code optimization files. If
+// any step fails, every file created or replaced so far is rolled back.
 func (g *Generator) InitializeProject(config *ProjectConfig) error {
-	// Create Claude Code optimization structure
-	if err := g.createClaudeStructure(".", config); err != nil {
-		return fmt.Errorf("failed to create Claude structure: %w", err)
+	plan, err := g.Plan(context.Background(), config)
+	if err != nil {
+		return err
+	}
+
+	return g.Apply(context.Background(), plan)
+}
+
+// generate adds every file for config to the current plan.
+func (g *Generator) generate(ctx context.Context, projectPath string, config *ProjectConfig) error {
+	projectType, err := g.projectType(config.Type)
+	if err != nil {
+		return err
+	}
+	g.addOns, err = resolveAddOns(projectType.Name, config.AddOns)
+	if err != nil {
+		return err
+	}
+	for _, addOn := range g.addOns {
+		projectType = withAddOn(projectType, addOn.Layer)
+	}
+	g.ptype = projectType
+	g.envVars = nil
+	g.generated = make(map[string]bool)
+	if g.workspace, err = g.detectWorkspace(projectPath); err != nil {
+		return fmt.Errorf("failed to detect workspace: %w", err)
 	}
 
 	// Generate Claude Code files
-	if err := g.generateClaudeFiles(".", config); err != nil {
+	if err := g.generateClaudeFiles(projectPath, config); err != nil {
 		return fmt.Errorf("failed to generate Claude files: %w", err)
 	}
 
 	// Generate development workflow files
-	if err := g.generateDevelopmentFiles(".", config); err != nil {
+	if err := g.generateDevelopmentFiles(projectPath, config); err != nil {
 		return fmt.Errorf("failed to generate development files: %w", err)
 	}
 
 	// Generate GitHub integration if requested
 	if config.GitHubUsername != "" {
-		if err := g.generateGitHubIntegration(".", config); err != nil {
+		if err := g.generateGitHubIntegration(projectPath, config); err != nil {
 			return fmt.Errorf("failed to generate GitHub integration: %w", err)
 		}
 	}
 
+	// Project type and registered generators run last so they can replace
+	// any of the files above
+	generators := append(append([]FileGenerator{}, projectType.Generators...), g.generators...)
+	for _, generate := range generators {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := g.runGenerator(ctx, projectPath, config, generate); err != nil {
+			return err
+		}
+	}
+
+	// After the generators, so patterns can match the files they scaffold
+	if err := g.generateCodeOwners(projectPath, config); err != nil {
+		return fmt.Errorf("failed to generate CODEOWNERS: %w", err)
+	}
+
+	// Last, so that variables read by the generated code are included too
+	if err := g.generateEnvExample(ctx, projectPath, config); err != nil {
+		return fmt.Errorf("failed to generate %s: %w", EnvExampleFile, err)
+	}
+
 	return nil
 }
 
@@ -70,7 +159,7 @@ func (g *Generator) CheckExistingClaudeFiles(dir string) []string {
 
 	var existing []string
 	for _, file := range claudeFiles {
-		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+		if _, err := g.FS.Stat(filepath.Join(dir, file)); err == nil {
 			existing = append(existing, file)
 		}
 	}
@@ -82,22 +171,7 @@ func (g *Generator) CheckExistingClaudeFiles(dir string) []string {
 
 // Removed createBaseStructure - using createClaudeStructure instead
 
-func (g *Generator) createClaudeStructure(projectPath string, config *ProjectConfig) error {
-	dirs := []string{
-		".claude",
-		".github",
-		".github/workflows",
-	}
-
-	for _, dir := range dirs {
-		dirPath := filepath.Join(projectPath, dir)
-		if err := os.MkdirAll(dirPath, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %w", dirPath, err)
-		}
-	}
-
-	return nil
-}
+// Removed createClaudeStructure - directories are created as files are applied
 
 // Removed generateFiles - functionality moved to InitializeProject and generateDevelopmentFiles
 
@@ -119,6 +193,18 @@ func (g *Generator) generateDevelopmentFiles(projectPath string, config *Project
 		return err
 	}
 
+	if config.ClaudeActions {
+		if err := g.generateClaudeActions(projectPath, config); err != nil {
+			return err
+		}
+	}
+
+	if config.Release {
+		if err := g.generateRelease(projectPath, config); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -133,6 +219,14 @@ func (g *Generator) generateClaudeFiles(projectPath string, config *ProjectConfi
 		return err
 	}
 
+	if err := g.generateClaudeSettings(projectPath, config); err != nil {
+		return err
+	}
+
+	if err := g.generatePackageClaudeMDs(projectPath, config); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -182,7 +276,31 @@ This project includes:
 - Pre-commit hooks for code quality
 - Claude Code configuration in ` + "`.claude/`" + ` directory
 
-## Getting Started
+{{if .ClaudeActions}}## GitHub Actions
+
+- ` + "`.github/workflows/claude-review.yml`" + ` - Claude reviews every non-draft pull request
+- ` + "`.github/workflows/claude.yml`" + ` - Claude responds when a maintainer mentions @claude in an issue or pull request
+
+Configure these repository secrets under Settings → Secrets and variables → Actions:
+
+- ` + "`{{.ClaudeSecret}}`" + ` - Anthropic API key used by both workflows
+
+Also install the Claude GitHub App (https://github.com/apps/claude) on the repository. Never commit API keys; the workflows only read them from secrets.
+
+{{end}}{{if .Workspace}}{{.Workspace}}
+{{end}}{{if .Environment}}{{.Environment}}
+{{end}}{{if .TypeNotes}}{{.TypeNotes}}
+{{end}}{{if .Release}}## Releases
+
+Releases are cut from tags. Commit messages follow conventional commits, which
+` + "`cliff.toml`" + ` turns into ` + "`CHANGELOG.md`" + `.
+
+- ` + "`make changelog`" + ` - Update CHANGELOG.md with unreleased changes
+- ` + "`make release VERSION=v1.2.3`" + ` - Update the changelog, commit and tag the release
+- ` + "`/release`" + ` - Have Claude walk through the release steps
+- ` + "`.github/workflows/release.yml`" + ` - Publishes a GitHub release when a ` + "`v*`" + ` tag is pushed
+
+{{end}}## Getting Started
 
 1. Install dependencies: ` + "`make install`" + `
 2. Start development: ` + "`make dev`" + `
@@ -208,22 +326,33 @@ For more information, see the ` + "`.claude/README.md`" + ` file for Claude Code
 	}
 
 	data := struct {
-		Name        string
-		Description string
-		Date        string
+		Name          string
+		Description   string
+		Date          string
+		ClaudeActions bool
+		ClaudeSecret  string
+		Release       bool
+		Workspace     string
+		Environment   string
+		TypeNotes     string
 	}{
-		Name:        config.Name,
-		Description: config.Description,
-		Date:        time.Now().Format("2006-01-02"),
+		Name:          config.Name,
+		Description:   config.Description,
+		Date:          g.Now().Format("2006-01-02"),
+		ClaudeActions: config.ClaudeActions,
+		ClaudeSecret:  ClaudeSecret,
+		Release:       config.Release,
+		Workspace:     workspaceNotes(g.workspace),
+		Environment:   envNotes(g.envVars),
+		TypeNotes:     g.ptype.Claude,
 	}
 
-	file, err := os.Create(filepath.Join(projectPath, "CLAUDE.md"))
-	if err != nil {
-		return fmt.Errorf("failed to create CLAUDE.md: %w", err)
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to render CLAUDE.md: %w", err)
 	}
-	defer file.Close()
 
-	return t.Execute(file, data)
+	return g.writeFile(filepath.Join(projectPath, "CLAUDE.md"), buf.String())
 }
 
 // Removed getTypeSpecificNotes - using generic approach now
@@ -231,6 +360,7 @@ For more information, see the ` + "`.claude/README.md`" + ` file for Claude Code
 func (g *Generator) generateGitIgnore(projectPath string, config *ProjectConfig) error {
 	content := `# Claude Code
 .claude/local/
+.claude/.cc-backups/
 *.claude-session
 
 # Common
@@ -260,11 +390,18 @@ htmlcov/
 
 # OS specific
 Thumbs.db
-
-# Add project-specific ignores below this line
 `
 
-	return g.writeFile(filepath.Join(projectPath, ".gitignore"), content)
+	if len(g.ptype.GitIgnore) > 0 {
+		content += "\n# " + g.ptype.Name + "\n" + strings.Join(g.ptype.GitIgnore, "\n") + "\n"
+	}
+	for _, addOn := range g.addOns {
+		if len(addOn.Layer.GitIgnore) > 0 {
+			content += "\n# " + addOn.Name + "\n" + strings.Join(addOn.Layer.GitIgnore, "\n") + "\n"
+		}
+	}
+
+	return g.writeBlock(filepath.Join(projectPath, ".gitignore"), content)
 }
 
 // Removed getTypeSpecificGitIgnore - using generic approach
@@ -302,7 +439,7 @@ repos:
         pass_filenames: false
 
 # Add project-specific pre-commit hooks below this line
-`
+` + g.ptype.PreCommit
 
 	return g.writeFile(filepath.Join(projectPath, ".pre-commit-config.yaml"), content)
 }
@@ -316,11 +453,8 @@ repos:
 // Removed generateTypeSpecificFiles - cc now focuses on Claude Code optimization only
 
 func (g *Generator) generateClaudeExamples(projectPath string, config *ProjectConfig) error {
-	// Just create the .claude directory - no examples needed
+	// Just the .claude directory - no examples needed
 	claudeDir := filepath.Join(projectPath, ".claude")
-	if err := os.MkdirAll(claudeDir, 0755); err != nil {
-		return fmt.Errorf("failed to create .claude directory: %w", err)
-	}
 
 	// Create a simple .claude/README.md explaining the directory
 	content := `# .claude Directory
@@ -343,79 +477,9 @@ based on your project's specific needs.
 	return g.writeFile(filepath.Join(claudeDir, "README.md"), content)
 }
 
-func (g *Generator) writeFile(path, content string) error {
-	return os.WriteFile(path, []byte(content), 0644)
-}
-
 func (g *Generator) generateGitHubIntegration(projectPath string, config *ProjectConfig) error {
-	// Generate .github/ISSUE_TEMPLATE directory
-	issueTemplateDir := filepath.Join(projectPath, ".github", "ISSUE_TEMPLATE")
-	if err := os.MkdirAll(issueTemplateDir, 0755); err != nil {
-		return fmt.Errorf("failed to create issue template directory: %w", err)
-	}
-
-	// Generate bug report template
-	bugReportContent := `---
-name: Bug report
-about: Create a report to help us improve
-title: ''
-labels: bug
-assignees: ` + config.GitHubUsername + `
-
----
-
-**Describe the bug**
-A clear and concise description of what the bug is.
-
-**To Reproduce**
-Steps to reproduce the behavior:
-1. Go to '...'
-2. Click on '....'
-3. Scroll down to '....'
-4. See error
-
-**Expected behavior**
-A clear and concise description of what you expected to happen.
-
-**Screenshots**
-If applicable, add screenshots to help explain your problem.
-
-**Environment (please complete the following information):**
- - OS: [e.g. iOS]
- - Version [e.g. 22]
-
-**Additional context**
-Add any other context about the problem here.
-`
-
-	if err := g.writeFile(filepath.Join(issueTemplateDir, "bug_report.md"), bugReportContent); err != nil {
-		return err
-	}
-
-	// Generate feature request template
-	featureRequestContent := `---
-name: Feature request
-about: Suggest an idea for this project
-title: ''
-labels: enhancement
-assignees: ` + config.GitHubUsername + `
-
----
-
-**Is your feature request related to a problem? Please describe.**
-A clear and concise description of what the problem is. Ex. I'm always frustrated when [...]
-
-**Describe the solution you'd like**
-A clear and concise description of what you want to happen.
-
-**Describe alternatives you've considered**
-A clear and concise description of any alternative solutions or features you've considered.
-
-**Additional context**
-Add any other context or screenshots about the feature request here.
-`
-
-	if err := g.writeFile(filepath.Join(issueTemplateDir, "feature_request.md"), featureRequestContent); err != nil {
+	// Generate issue forms, or markdown templates
+	if err := g.generateIssueTemplates(projectPath, config); err != nil {
 		return err
 	}
 
@@ -465,7 +529,7 @@ First off, thank you for considering contributing to ` + config.Name + `! It's p
 
 ## Where do I go from here?
 
-If you've noticed a bug or have a feature request, make sure to check our [Issues](https://github.com/` + config.GitHubUsername + `/` + config.Name + `/issues) if there's something similar to what you have in mind. If there isn't, feel free to open a new issue!
+If you've noticed a bug or have a feature request, make sure to check our [Issues](https://` + config.GitHubHost + `/` + config.GitHubUsername + `/` + config.GitHubRepo + `/issues) if there's something similar to what you have in mind. If there isn't, feel free to open a new issue!
 
 ## Fork & create a branch
 
@@ -507,7 +571,7 @@ make lint
 At this point, you should switch back to your main branch and make sure it's up to date with the latest ` + config.Name + ` main branch:
 
 ` + "```bash" + `
-git remote add upstream git@github.com:` + config.GitHubUsername + `/` + config.Name + `.git
+git remote add upstream git@` + config.GitHubHost + `:` + config.GitHubUsername + `/` + config.GitHubRepo + `.git
 git checkout main
 git pull upstream main
 ` + "```" + `
@@ -569,83 +633,7 @@ SOFTWARE.
 	return g.writeFile(filepath.Join(projectPath, "LICENSE"), licenseContent)
 }
 
-func (g *Generator) generateGenericMakefile(projectPath string, config *ProjectConfig) error {
-	content := `# Makefile for ` + config.Name + `
-# Generated by cc - Claude Code optimization tool
-
-.PHONY: help install dev test lint build clean
-
-help:
-	@echo "Available commands:"
-	@echo "  make install   - Install dependencies"
-	@echo "  make dev       - Start development environment"
-	@echo "  make test      - Run tests"
-	@echo "  make lint      - Run linting and formatting"
-	@echo "  make build     - Build the project"
-	@echo "  make clean     - Clean build artifacts"
-
-install:
-	@echo "Installing dependencies..."
-	@echo "Add your dependency installation commands here"
-
-dev:
-	@echo "Starting development environment..."
-	@echo "Add your development startup commands here"
-
-test:
-	@echo "Running tests..."
-	@echo "Add your test commands here"
-
-lint:
-	@echo "Running linting and formatting..."
-	@echo "Add your linting commands here"
-
-build:
-	@echo "Building project..."
-	@echo "Add your build commands here"
-
-clean:
-	@echo "Cleaning build artifacts..."
-	rm -rf dist/ build/ *.egg-info/ target/
-	find . -type d -name __pycache__ -exec rm -rf {} + 2>/dev/null || true
-	find . -type f -name "*.pyc" -delete 2>/dev/null || true
-`
-
-	return g.writeFile(filepath.Join(projectPath, "Makefile"), content)
-}
-
-func (g *Generator) generateGenericGitHubWorkflow(projectPath string, config *ProjectConfig) error {
-	workflowDir := filepath.Join(projectPath, ".github", "workflows")
-	if err := os.MkdirAll(workflowDir, 0755); err != nil {
-		return fmt.Errorf("failed to create workflow directory: %w", err)
-	}
-
-	content := `name: CI
-
-on:
-  push:
-    branches: [main]
-  pull_request:
-    branches: [main]
-
-jobs:
-  test:
-    runs-on: ubuntu-latest
-    steps:
-      - uses: actions/checkout@v4
-      
-      - name: Run tests
-        run: make test
-        
-      - name: Run linting  
-        run: make lint
-        
-      - name: Build project
-        run: make build
-`
-
-	return g.writeFile(filepath.Join(workflowDir, "ci.yml"), content)
-}
+// Removed generateGenericGitHubWorkflow - CI workflows are rendered in ci.go
 
 // Type-specific file generators are implemented in separate files
 
@@ -0,0 +1,424 @@
+package generator
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata/golden")
+
+const testRoot = "/project"
+
+func newTestGenerator(fsys FS) *Generator {
+	gen := New()
+	gen.Log = io.Discard
+	gen.FS = fsys
+	gen.Now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return gen
+}
+
+func newMemFS(t *testing.T, files map[string]string) afero.Fs {
+	t.Helper()
+
+	mem := afero.NewMemMapFs()
+	if err := mem.MkdirAll(testRoot, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		full := filepath.Join(testRoot, path)
+		if err := mem.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := afero.WriteFile(mem, full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return mem
+}
+
+// snapshot returns every file under the test root keyed by relative path,
+// with directories recorded as an empty value with a trailing slash.
+func snapshot(t *testing.T, mem afero.Fs, skipBackups bool) map[string]string {
+	t.Helper()
+
+	files := make(map[string]string)
+	err := afero.Walk(mem, testRoot, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(testRoot, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if skipBackups && isBackupPath(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			files[rel+"/"] = ""
+			return nil
+		}
+		data, err := afero.ReadFile(mem, path)
+		files[rel] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// renderGolden serializes files as a sequence of "-- path --" sections.
+func renderGolden(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		if !strings.HasSuffix(path, "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, path := range paths {
+		b.WriteString("-- " + path + " --\n")
+		b.WriteString(files[path])
+		if !strings.HasSuffix(files[path], "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test -update to accept):\n%s", path, diffLines(string(want), got))
+	}
+}
+
+// diffLines reports the first line where want and got differ.
+func diffLines(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+type goldenCase struct {
+	name   string
+	config ProjectConfig
+	files  map[string]string
+}
+
+func goldenCases() []goldenCase {
+	cases := []goldenCase{
+		{
+			name:   "generic",
+			config: ProjectConfig{Name: "example", Description: "An example project"},
+		},
+		{
+			name:   "generic-github",
+			config: ProjectConfig{Name: "example", Description: "An example project", GitHubUsername: "octocat"},
+		},
+		{
+			name:   "generic-merge",
+			config: ProjectConfig{Name: "example", Description: "An example project"},
+			files: map[string]string{
+				".gitignore": "/bin/\n",
+				"Makefile":   "test:\n\tgo test ./...\n",
+			},
+		},
+		{
+			name:   "generic-remote",
+			config: ProjectConfig{Name: "example", Description: "An example project", GitHubUsername: "acme"},
+			files: map[string]string{
+				".git/config": "[remote \"origin\"]\n\turl = git@github.example.com:acme/widgets.git\n",
+			},
+		},
+		{
+			name: "generic-ci-stacks",
+			config: ProjectConfig{
+				Name:        "example",
+				Description: "An example project",
+				CIMatrix: []MatrixAxis{
+					{Name: "os", Values: []string{"ubuntu-latest", "macos-latest"}},
+					{Name: "go", Values: []string{"1.23", "1.24"}},
+				},
+			},
+			files: map[string]string{
+				"go.mod":            "module example.com/example\n",
+				"package.json":      "{}\n",
+				"package-lock.json": "{}\n",
+				"pyproject.toml":    "[project]\nname = \"example\"\n",
+			},
+		},
+		{
+			name:   "generic-claude-actions",
+			config: ProjectConfig{Name: "example", Description: "An example project", GitHubUsername: "octocat", ClaudeActions: true},
+		},
+		{
+			name:   "generic-release",
+			config: ProjectConfig{Name: "example", Description: "An example project", Release: true},
+		},
+		{
+			name:   "generic-release-go",
+			config: ProjectConfig{Name: "example", Description: "An example project", Release: true},
+			files: map[string]string{
+				"go.mod":              "module example.com/example\n",
+				"cmd/example/main.go": "package main\n\nfunc main() {}\n",
+			},
+		},
+		{
+			name:   "generic-workspace",
+			config: ProjectConfig{Name: "example", Description: "An example project"},
+			files: map[string]string{
+				"go.work":          "go 1.24\n\nuse (\n\t./api\n\t./worker\n)\n",
+				"api/go.mod":       "module example.com/api\n",
+				"worker/go.mod":    "module example.com/worker\n",
+				"worker/Makefile":  "test:\n\tgo test -race ./...\n",
+				"package.json":     `{"workspaces": ["web"]}`,
+				"web/package.json": `{"name": "web", "scripts": {"test": "vitest", "build": "vite build"}}`,
+			},
+		},
+	}
+
+	cases = append(cases, goldenCase{
+		name:   "kubernetes-helm",
+		config: ProjectConfig{Name: "example", Type: "kubernetes", Description: "An example project", AddOns: []string{"helm"}},
+	}, goldenCase{
+		name:   "go-dagger",
+		config: ProjectConfig{Name: "example", Type: "go", Description: "An example project", Module: "example.com/example", AddOns: []string{"dagger"}},
+	}, goldenCase{
+		name:   "go-addons",
+		config: ProjectConfig{Name: "example", Type: "go", Description: "An example project", Module: "example.com/example", AddOns: []string{"docker", "helm", "terraform", "openapi", "postgres"}},
+	})
+
+	for _, t := range ProjectTypes() {
+		cases = append(cases, goldenCase{
+			name:   t.Name,
+			config: ProjectConfig{Name: "example", Type: t.Name, Description: "An example project", GitHubUsername: "octocat"},
+		})
+	}
+
+	return cases
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := newMemFS(t, tc.files)
+			gen := newTestGenerator(AferoFS{Fs: mem})
+
+			config := tc.config
+			config.Root = testRoot
+			if err := gen.InitializeProject(&config); err != nil {
+				t.Fatal(err)
+			}
+
+			checkGolden(t, tc.name, renderGolden(snapshot(t, mem, true)))
+		})
+	}
+}
+
+// failingFS fails every write to a file with the given base name.
+type failingFS struct {
+	FS
+	name string
+}
+
+func (f failingFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if filepath.Base(name) == f.name {
+		return errors.New("injected failure")
+	}
+	return f.FS.WriteFile(name, data, perm)
+}
+
+func TestInitializeProjectRollsBackOnFailure(t *testing.T) {
+	mem := newMemFS(t, map[string]string{
+		".gitignore": "/bin/\n",
+		"CLAUDE.md":  "# Existing\n",
+	})
+	before := snapshot(t, mem, false)
+
+	gen := newTestGenerator(failingFS{FS: AferoFS{Fs: mem}, name: "CONTRIBUTING.md"})
+	err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example", GitHubUsername: "octocat", Overwrite: true})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	after := snapshot(t, mem, false)
+	if renderGolden(after) != renderGolden(before) || len(after) != len(before) {
+		t.Errorf("tree not restored:\nbefore: %v\nafter:  %v", keys(before), keys(after))
+	}
+}
+
+func TestUndoRestoresTree(t *testing.T) {
+	mem := newMemFS(t, map[string]string{
+		".gitignore": "/bin/\n",
+		"Makefile":   "test:\n\tgo test ./...\n",
+	})
+	before := snapshot(t, mem, false)
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "renamed", Overwrite: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := gen.ListRuns(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(runs))
+	}
+
+	for range runs {
+		if _, err := gen.Undo(testRoot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if after := snapshot(t, mem, false); renderGolden(after) != renderGolden(before) || len(after) != len(before) {
+		t.Errorf("tree not restored:\nbefore: %v\nafter:  %v", keys(before), keys(after))
+	}
+}
+
+func TestListRunsOrdersRunsWithinOneSecond(t *testing.T) {
+	files := make(map[string]string)
+	for _, id := range []string{"20240102T030405Z", "20240102T030405Z-9", "20240102T030405Z-10", "20240101T000000Z-11"} {
+		stamp, _, _ := strings.Cut(id, "-")
+		at, err := time.Parse("20060102T150405Z", stamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filepath.Join(BackupDir, id, runFile)] = fmt.Sprintf(`{"id": %q, "time": %q}`, id, at.Format(time.RFC3339))
+	}
+	gen := newTestGenerator(AferoFS{Fs: newMemFS(t, files)})
+
+	runs, err := gen.ListRuns(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, run := range runs {
+		got = append(got, run.ID)
+	}
+	want := []string{"20240102T030405Z-10", "20240102T030405Z-9", "20240102T030405Z", "20240101T000000Z-11"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("runs = %q, want %q", got, want)
+	}
+}
+
+func TestUninstallKeepsModifiedFiles(t *testing.T) {
+	mem := newMemFS(t, map[string]string{".gitignore": "/bin/\n"})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(mem, filepath.Join(testRoot, "CLAUDE.md"), []byte("# Mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := gen.Uninstall(testRoot, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Modified) != 1 || result.Modified[0] != "CLAUDE.md" {
+		t.Errorf("Modified = %v, want [CLAUDE.md]", result.Modified)
+	}
+
+	got := snapshot(t, mem, false)
+	if got[".gitignore"] != "/bin/\n" || got["CLAUDE.md"] != "# Mine\n" {
+		t.Errorf("unexpected content after uninstall: %v", got)
+	}
+	if _, ok := got["Makefile"]; ok {
+		t.Error("Makefile was not removed")
+	}
+
+	if _, err := gen.Uninstall(testRoot, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshot(t, mem, false); len(got) != 1 || got[".gitignore"] != "/bin/\n" {
+		t.Errorf("tree after forced uninstall: %v", keys(got))
+	}
+}
+
+func TestTerraformDenyRulesReachExistingSettings(t *testing.T) {
+	mem := newMemFS(t, map[string]string{".claude/settings.json": `{"permissions": {"allow": ["Bash(ls)"]}}`})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example", Type: "terraform"}); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := snapshot(t, mem, false)[".claude/settings.json"]
+	for _, rule := range []string{
+		`"Bash(ls)"`,
+		`"Bash(terraform apply:*)"`,
+		`"Bash(terraform -chdir=environments/prod apply:*)"`,
+		`"Bash(terraform -chdir=environments/dev destroy:*)"`,
+	} {
+		if !strings.Contains(settings, rule) {
+			t.Errorf("settings.json has no %s:\n%s", rule, settings)
+		}
+	}
+	if strings.Contains(settings, "make apply") {
+		t.Errorf("settings.json denies a make target that does not exist:\n%s", settings)
+	}
+}
+
+func keys(files map[string]string) []string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
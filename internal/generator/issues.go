@@ -0,0 +1,380 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue template styles.
+const (
+	IssueTemplatesForms    = "forms"
+	IssueTemplatesMarkdown = "markdown"
+)
+
+// IssueField is an extra input a project type adds to the bug report form,
+// such as the language or tool version. Fields with Options are dropdowns.
+type IssueField struct {
+	ID          string
+	Label       string
+	Description string
+	Placeholder string
+	Options     []string
+	Required    bool
+}
+
+// issueForm is a GitHub issue form, see
+// https://docs.github.com/en/communities/using-templates-to-encourage-useful-issues-and-pull-requests/syntax-for-issue-forms
+type issueForm struct {
+	Name        string           `yaml:"name"`
+	Description string           `yaml:"description"`
+	Labels      []string         `yaml:"labels,omitempty"`
+	Assignees   []string         `yaml:"assignees,omitempty"`
+	Body        []issueFormInput `yaml:"body"`
+}
+
+type issueFormInput struct {
+	Type        string               `yaml:"type"`
+	ID          string               `yaml:"id,omitempty"`
+	Attributes  issueFormAttributes  `yaml:"attributes"`
+	Validations *issueFormValidation `yaml:"validations,omitempty"`
+}
+
+type issueFormAttributes struct {
+	Label       string   `yaml:"label,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Placeholder string   `yaml:"placeholder,omitempty"`
+	Value       string   `yaml:"value,omitempty"`
+	Render      string   `yaml:"render,omitempty"`
+	Options     []string `yaml:"options,omitempty"`
+}
+
+type issueFormValidation struct {
+	Required bool `yaml:"required"`
+}
+
+// issueConfig is ISSUE_TEMPLATE/config.yml, which configures the template
+// chooser.
+type issueConfig struct {
+	BlankIssuesEnabled bool               `yaml:"blank_issues_enabled"`
+	ContactLinks       []issueContactLink `yaml:"contact_links,omitempty"`
+}
+
+type issueContactLink struct {
+	Name  string `yaml:"name"`
+	URL   string `yaml:"url"`
+	About string `yaml:"about"`
+}
+
+func markdown(value string) issueFormInput {
+	return issueFormInput{Type: "markdown", Attributes: issueFormAttributes{Value: value}}
+}
+
+func textarea(id, label, description string, required bool) issueFormInput {
+	input := issueFormInput{Type: "textarea", ID: id, Attributes: issueFormAttributes{Label: label, Description: description}}
+	if required {
+		input.Validations = &issueFormValidation{Required: true}
+	}
+	return input
+}
+
+func (f IssueField) input() issueFormInput {
+	input := issueFormInput{
+		Type: "input",
+		ID:   f.ID,
+		Attributes: issueFormAttributes{
+			Label:       f.Label,
+			Description: f.Description,
+			Placeholder: f.Placeholder,
+		},
+	}
+	if len(f.Options) > 0 {
+		input.Type = "dropdown"
+		input.Attributes.Placeholder = ""
+		input.Attributes.Options = f.Options
+	}
+	if f.Required {
+		input.Validations = &issueFormValidation{Required: true}
+	}
+	return input
+}
+
+func (g *Generator) generateIssueTemplates(projectPath string, config *ProjectConfig) error {
+	issueTemplateDir := filepath.Join(projectPath, ".github", "ISSUE_TEMPLATE")
+
+	switch config.IssueTemplates {
+	case "", IssueTemplatesForms:
+	case IssueTemplatesMarkdown:
+		return g.generateMarkdownIssueTemplates(issueTemplateDir, config)
+	default:
+		return fmt.Errorf("invalid issue template style %q (must be %s or %s)", config.IssueTemplates, IssueTemplatesForms, IssueTemplatesMarkdown)
+	}
+
+	var assignees []string
+	if config.GitHubUsername != "" {
+		assignees = []string{config.GitHubUsername}
+	}
+
+	bugBody := []issueFormInput{
+		markdown("Thanks for taking the time to report a bug! Please check the existing issues first to avoid duplicates."),
+		textarea("what-happened", "What happened?", "A clear and concise description of the bug.", true),
+		textarea("reproduce", "Steps to reproduce", "The smallest set of steps or code that shows the problem.", true),
+		textarea("expected", "Expected behavior", "What you expected to happen instead.", true),
+		IssueField{ID: "version", Label: "Version", Description: "The release, tag or commit where you saw the bug.", Placeholder: "v1.2.3"}.input(),
+	}
+	for _, field := range g.ptype.IssueFields {
+		bugBody = append(bugBody, field.input())
+	}
+	logs := textarea("logs", "Relevant log output", "Paste any error messages or logs. This is rendered as code, so no backticks are needed.", false)
+	logs.Attributes.Render = "shell"
+	bugBody = append(bugBody, logs, textarea("context", "Additional context", "Anything else that might help, such as screenshots or configuration.", false))
+
+	forms := []struct {
+		name string
+		form issueForm
+	}{
+		{"bug_report.yml", issueForm{
+			Name:        "Bug report",
+			Description: "Report something that is not working as expected",
+			Labels:      []string{"bug"},
+			Assignees:   assignees,
+			Body:        bugBody,
+		}},
+		{"feature_request.yml", issueForm{
+			Name:        "Feature request",
+			Description: "Suggest an idea for " + config.Name,
+			Labels:      []string{"enhancement"},
+			Assignees:   assignees,
+			Body: []issueFormInput{
+				textarea("problem", "What problem would this solve?", "Describe the problem or limitation you are running into.", true),
+				textarea("solution", "Proposed solution", "What you would like to happen.", true),
+				textarea("alternatives", "Alternatives considered", "Other solutions or workarounds you have tried.", false),
+				IssueField{ID: "priority", Label: "How important is this to you?", Options: []string{"Nice to have", "Important", "Blocking"}}.input(),
+			},
+		}},
+	}
+
+	for _, f := range forms {
+		content, err := marshalYAML(f.form)
+		if err != nil {
+			return err
+		}
+		if err := validateIssueForm(content); err != nil {
+			return fmt.Errorf("invalid issue form %s: %w", f.name, err)
+		}
+		if err := g.writeFile(filepath.Join(issueTemplateDir, f.name), content); err != nil {
+			return err
+		}
+	}
+
+	// Forms replace blank issues; questions go to the contributing guide
+	chooser := issueConfig{
+		BlankIssuesEnabled: false,
+		ContactLinks: []issueContactLink{{
+			Name:  "Contributing guide",
+			URL:   fmt.Sprintf("https://%s/%s/%s/blob/main/CONTRIBUTING.md", config.GitHubHost, config.GitHubUsername, config.GitHubRepo),
+			About: "How to set up the project, run the tests and open a pull request.",
+		}},
+	}
+	content, err := marshalYAML(chooser)
+	if err != nil {
+		return err
+	}
+	if err := validateIssueConfig(content); err != nil {
+		return fmt.Errorf("invalid issue template config: %w", err)
+	}
+
+	return g.writeFile(filepath.Join(issueTemplateDir, "config.yml"), content)
+}
+
+func marshalYAML(v any) (string, error) {
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var issueFormIDRe = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validateIssueForm checks an issue form against the rules of GitHub's issue
+// form schema that a generated form could break.
+func validateIssueForm(content string) error {
+	var form map[string]any
+	if err := yaml.Unmarshal([]byte(content), &form); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"name", "description"} {
+		if s, _ := form[key].(string); strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", key)
+		}
+	}
+
+	body, _ := form["body"].([]any)
+	if len(body) == 0 {
+		return fmt.Errorf("body must have at least one element")
+	}
+
+	ids := make(map[string]bool)
+	labels := make(map[string]bool)
+	nonMarkdown := 0
+	for i, raw := range body {
+		element, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("body[%d] is not a mapping", i)
+		}
+		kind, _ := element["type"].(string)
+		attributes, _ := element["attributes"].(map[string]any)
+		if attributes == nil {
+			return fmt.Errorf("body[%d] has no attributes", i)
+		}
+
+		if id, ok := element["id"]; ok {
+			s, _ := id.(string)
+			if !issueFormIDRe.MatchString(s) {
+				return fmt.Errorf("body[%d] has invalid id %q", i, s)
+			}
+			if ids[s] {
+				return fmt.Errorf("duplicate id %q", s)
+			}
+			ids[s] = true
+		}
+
+		switch kind {
+		case "markdown":
+			if s, _ := attributes["value"].(string); s == "" {
+				return fmt.Errorf("body[%d] markdown needs a value", i)
+			}
+			continue
+		case "textarea", "input":
+		case "dropdown", "checkboxes":
+			options, _ := attributes["options"].([]any)
+			if len(options) == 0 {
+				return fmt.Errorf("body[%d] %s needs options", i, kind)
+			}
+		default:
+			return fmt.Errorf("body[%d] has unknown type %q", i, kind)
+		}
+
+		nonMarkdown++
+		label, _ := attributes["label"].(string)
+		if label == "" {
+			return fmt.Errorf("body[%d] %s needs a label", i, kind)
+		}
+		if labels[label] {
+			return fmt.Errorf("duplicate label %q", label)
+		}
+		labels[label] = true
+	}
+	if nonMarkdown == 0 {
+		return fmt.Errorf("body needs at least one non-markdown element")
+	}
+
+	return nil
+}
+
+// validateIssueConfig checks an ISSUE_TEMPLATE/config.yml.
+func validateIssueConfig(content string) error {
+	var config map[string]any
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return err
+	}
+
+	if v, ok := config["blank_issues_enabled"]; ok {
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("blank_issues_enabled must be a boolean")
+		}
+	}
+
+	links, _ := config["contact_links"].([]any)
+	for i, raw := range links {
+		link, _ := raw.(map[string]any)
+		for _, key := range []string{"name", "url", "about"} {
+			if s, _ := link[key].(string); s == "" {
+				return fmt.Errorf("contact_links[%d] needs %s", i, key)
+			}
+		}
+		if url, _ := link["url"].(string); !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			return fmt.Errorf("contact_links[%d] url must be absolute", i)
+		}
+	}
+
+	return nil
+}
+
+// generateMarkdownIssueTemplates writes the legacy markdown templates, for
+// projects that prefer them over issue forms.
+func (g *Generator) generateMarkdownIssueTemplates(issueTemplateDir string, config *ProjectConfig) error {
+	// Generate bug report template
+	bugReportContent := `---
+name: Bug report
+about: Create a report to help us improve
+title: ''
+labels: bug
+assignees: ` + config.GitHubUsername + `
+
+---
+
+**Describe the bug**
+A clear and concise description of what the bug is.
+
+**To Reproduce**
+Steps to reproduce the behavior:
+1. Go to '...'
+2. Click on '....'
+3. Scroll down to '....'
+4. See error
+
+**Expected behavior**
+A clear and concise description of what you expected to happen.
+
+**Screenshots**
+If applicable, add screenshots to help explain your problem.
+
+**Environment (please complete the following information):**
+ - OS: [e.g. iOS]
+ - Version [e.g. 22]
+
+**Additional context**
+Add any other context about the problem here.
+`
+
+	if err := g.writeFile(filepath.Join(issueTemplateDir, "bug_report.md"), bugReportContent); err != nil {
+		return err
+	}
+
+	// Generate feature request template
+	featureRequestContent := `---
+name: Feature request
+about: Suggest an idea for this project
+title: ''
+labels: enhancement
+assignees: ` + config.GitHubUsername + `
+
+---
+
+**Is your feature request related to a problem? Please describe.**
+A clear and concise description of what the problem is. Ex. I'm always frustrated when [...]
+
+**Describe the solution you'd like**
+A clear and concise description of what you want to happen.
+
+**Describe alternatives you've considered**
+A clear and concise description of any alternative solutions or features you've considered.
+
+**Additional context**
+Add any other context or screenshots about the feature request here.
+`
+
+	return g.writeFile(filepath.Join(issueTemplateDir, "feature_request.md"), featureRequestContent)
+}
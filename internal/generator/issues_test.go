@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIssueForms(t *testing.T) {
+	gen := newTestGenerator(AferoFS{Fs: newMemFS(t, nil)})
+	err := gen.RegisterProjectType(ProjectType{
+		Name: "tool",
+		IssueFields: []IssueField{
+			{ID: "tool-version", Label: "Tool version", Required: true},
+			{ID: "os", Label: "Operating system", Options: []string{"Linux", "macOS", "Windows"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := gen.Plan(t.Context(), &ProjectConfig{Root: testRoot, Name: "example", Type: "tool", GitHubUsername: "octocat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, file := range plan.Files {
+		files[file.Path] = string(file.Content)
+	}
+
+	bug := files[".github/ISSUE_TEMPLATE/bug_report.yml"]
+	if err := validateIssueForm(bug); err != nil {
+		t.Errorf("bug report: %v", err)
+	}
+	for _, want := range []string{"id: tool-version", "type: dropdown", "- Windows"} {
+		if !strings.Contains(bug, want) {
+			t.Errorf("bug report does not contain %q:\n%s", want, bug)
+		}
+	}
+	if err := validateIssueForm(files[".github/ISSUE_TEMPLATE/feature_request.yml"]); err != nil {
+		t.Errorf("feature request: %v", err)
+	}
+	if err := validateIssueConfig(files[".github/ISSUE_TEMPLATE/config.yml"]); err != nil {
+		t.Errorf("config: %v", err)
+	}
+	if _, ok := files[".github/ISSUE_TEMPLATE/bug_report.md"]; ok {
+		t.Error("markdown template generated alongside forms")
+	}
+}
+
+func TestIssueFormsMarkdownFallback(t *testing.T) {
+	gen := newTestGenerator(AferoFS{Fs: newMemFS(t, nil)})
+
+	plan, err := gen.Plan(t.Context(), &ProjectConfig{Root: testRoot, Name: "example", GitHubUsername: "octocat", IssueTemplates: IssueTemplatesMarkdown})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, file := range plan.Files {
+		if strings.HasPrefix(file.Path, ".github/ISSUE_TEMPLATE/") {
+			paths = append(paths, file.Path)
+		}
+	}
+	if strings.Join(paths, " ") != ".github/ISSUE_TEMPLATE/bug_report.md .github/ISSUE_TEMPLATE/feature_request.md" {
+		t.Errorf("issue templates = %v", paths)
+	}
+}
+
+func TestValidateIssueForm(t *testing.T) {
+	tests := []struct {
+		name    string
+		form    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			form: "name: Bug\ndescription: A bug\nbody:\n  - type: input\n    id: v\n    attributes:\n      label: Version\n",
+		},
+		{
+			name:    "missing description",
+			form:    "name: Bug\nbody:\n  - type: input\n    attributes:\n      label: Version\n",
+			wantErr: "description is required",
+		},
+		{
+			name:    "duplicate id",
+			form:    "name: Bug\ndescription: A bug\nbody:\n  - type: input\n    id: v\n    attributes:\n      label: A\n  - type: input\n    id: v\n    attributes:\n      label: B\n",
+			wantErr: `duplicate id "v"`,
+		},
+		{
+			name:    "dropdown without options",
+			form:    "name: Bug\ndescription: A bug\nbody:\n  - type: dropdown\n    attributes:\n      label: OS\n",
+			wantErr: "needs options",
+		},
+		{
+			name:    "markdown only",
+			form:    "name: Bug\ndescription: A bug\nbody:\n  - type: markdown\n    attributes:\n      value: Hi\n",
+			wantErr: "at least one non-markdown",
+		},
+		{
+			name:    "unknown type",
+			form:    "name: Bug\ndescription: A bug\nbody:\n  - type: select\n    attributes:\n      label: OS\n",
+			wantErr: `unknown type "select"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateIssueForm(tt.form)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("validateIssueForm() = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("validateIssueForm() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIssueFormsIncludeAddOnFields(t *testing.T) {
+	err := RegisterAddOn(AddOn{
+		Name:  "queue",
+		Layer: ProjectType{IssueFields: []IssueField{{ID: "broker", Label: "Message broker", Options: []string{"RabbitMQ", "NATS"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(addOns, "queue")
+	})
+
+	gen := newTestGenerator(AferoFS{Fs: newMemFS(t, nil)})
+	plan, err := gen.Plan(t.Context(), &ProjectConfig{Root: testRoot, Name: "example", Type: "go", AddOns: []string{"queue"}, GitHubUsername: "octocat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range plan.Files {
+		if file.Path != ".github/ISSUE_TEMPLATE/bug_report.yml" {
+			continue
+		}
+		// The type's fields come first, then the add-on's
+		bug := string(file.Content)
+		goVersion, broker := strings.Index(bug, "id: go-version"), strings.Index(bug, "id: broker")
+		if goVersion < 0 || broker < goVersion {
+			t.Errorf("bug report lacks the type's or the add-on's fields:\n%s", bug)
+		}
+		return
+	}
+	t.Fatal("no bug report form planned")
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// journal records every change made to the filesystem during a run so that a
+// failed run can be rolled back, leaving the tree exactly as it was before.
+type journal struct {
+	fs      FS
+	entries []journalEntry
+	seen    map[string]bool
+}
+
+type journalEntry struct {
+	path     string
+	dir      bool
+	created  bool
+	removed  bool
+	original []byte
+	mode     fs.FileMode
+}
+
+func newJournal(fsys FS) *journal {
+	return &journal{fs: fsys, seen: make(map[string]bool)}
+}
+
+// recordFile must be called before path is written or removed. Only the first
+// change to a path is recorded, since that is the state rollback has to restore.
+func (j *journal) recordFile(path string) error {
+	if j.seen[path] {
+		return nil
+	}
+
+	info, err := j.fs.Stat(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		j.add(journalEntry{path: path, created: true})
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	case info.IsDir():
+		return fmt.Errorf("cannot write %s: is a directory", path)
+	}
+
+	original, err := j.fs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	j.add(journalEntry{path: path, original: original, mode: info.Mode().Perm()})
+	return nil
+}
+
+func (j *journal) recordDir(path string) {
+	if j.seen[path] {
+		return
+	}
+	j.add(journalEntry{path: path, dir: true, created: true})
+}
+
+// recordDirRemoval must be called before an empty directory is removed.
+func (j *journal) recordDirRemoval(path string) {
+	j.add(journalEntry{path: path, dir: true, removed: true})
+}
+
+func (j *journal) add(entry journalEntry) {
+	j.seen[entry.path] = true
+	j.entries = append(j.entries, entry)
+}
+
+// rollback undoes the recorded changes in reverse order: created files and
+// directories are removed, removed directories are recreated and replaced or
+// removed files get their original content back.
+func (j *journal) rollback() error {
+	var errs []error
+
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		entry := j.entries[i]
+
+		switch {
+		case entry.removed:
+			if err := j.fs.Mkdir(entry.path, 0755); err != nil && !errors.Is(err, fs.ErrExist) {
+				errs = append(errs, fmt.Errorf("failed to recreate %s: %w", entry.path, err))
+			}
+		case entry.created:
+			if err := j.fs.Remove(entry.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("failed to remove %s: %w", entry.path, err))
+			}
+		default:
+			if err := j.fs.WriteFile(entry.path, entry.original, entry.mode); err != nil {
+				errs = append(errs, fmt.Errorf("failed to restore %s: %w", entry.path, err))
+			}
+		}
+	}
+
+	j.entries = nil
+	j.seen = make(map[string]bool)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,189 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MakeTarget is a rule in the generated Makefile. Help is shown by make help.
+type MakeTarget struct {
+	Name   string
+	Help   string
+	Recipe []string
+}
+
+func genericMakeTargets() []MakeTarget {
+	return []MakeTarget{
+		{
+			Name: "install",
+			Help: "Install dependencies",
+			Recipe: []string{
+				`@echo "Installing dependencies..."`,
+				`@echo "Add your dependency installation commands here"`,
+			},
+		},
+		{
+			Name: "dev",
+			Help: "Start development environment",
+			Recipe: []string{
+				`@echo "Starting development environment..."`,
+				`@echo "Add your development startup commands here"`,
+			},
+		},
+		{
+			Name: "test",
+			Help: "Run tests",
+			Recipe: []string{
+				`@echo "Running tests..."`,
+				`@echo "Add your test commands here"`,
+			},
+		},
+		{
+			Name: "lint",
+			Help: "Run linting and formatting",
+			Recipe: []string{
+				`@echo "Running linting and formatting..."`,
+				`@echo "Add your linting commands here"`,
+			},
+		},
+		{
+			Name: "build",
+			Help: "Build the project",
+			Recipe: []string{
+				`@echo "Building project..."`,
+				`@echo "Add your build commands here"`,
+			},
+		},
+		{
+			Name: "clean",
+			Help: "Clean build artifacts",
+			Recipe: []string{
+				`@echo "Cleaning build artifacts..."`,
+				`rm -rf dist/ build/ *.egg-info/ target/`,
+				`find . -type d -name __pycache__ -exec rm -rf {} + 2>/dev/null || true`,
+				`find . -type f -name "*.pyc" -delete 2>/dev/null || true`,
+			},
+		},
+	}
+}
+
+func (g *Generator) generateGenericMakefile(projectPath string, config *ProjectConfig) error {
+	path := filepath.Join(projectPath, "Makefile")
+
+	// When merging into an existing Makefile, leave the user's own targets
+	// alone instead of redefining them
+	defined := map[string]bool{}
+	if !config.Overwrite {
+		existing, _, err := g.readDisk(path)
+		if err != nil {
+			return err
+		}
+		defined = definedMakeTargets(stripBlock(existing))
+	}
+
+	// A monorepo's test, lint and build run in every package, unless the
+	// project type says otherwise
+	candidates := mergeMakeTargets(mergeMakeTargets(genericMakeTargets(), workspaceMakeTargets(g.workspace)), g.ptype.MakeTargets)
+	if config.Release {
+		candidates = append(candidates, releaseMakeTargets()...)
+	}
+
+	var targets []MakeTarget
+	for _, target := range candidates {
+		if !defined[target.Name] {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return g.skipFile(path)
+	}
+
+	return g.writeBlock(path, renderMakefile(config.Name, g.ptype.MakeVars, targets, !defined["help"]))
+}
+
+// mergeMakeTargets replaces the targets in base that extra redefines and adds
+// the rest of extra after them.
+func mergeMakeTargets(base, extra []MakeTarget) []MakeTarget {
+	replaced := make(map[string]bool)
+	merged := make([]MakeTarget, 0, len(base)+len(extra))
+	for _, target := range base {
+		for _, override := range extra {
+			if override.Name == target.Name {
+				target = override
+				replaced[target.Name] = true
+				break
+			}
+		}
+		merged = append(merged, target)
+	}
+	for _, target := range extra {
+		if !replaced[target.Name] {
+			merged = append(merged, target)
+		}
+	}
+	return merged
+}
+
+func renderMakefile(name string, vars []string, targets []MakeTarget, withHelp bool) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Makefile for %s\n", name)
+	b.WriteString("# Generated by cc - Claude Code optimization tool\n\n")
+	if len(vars) > 0 {
+		b.WriteString(strings.Join(vars, "\n") + "\n\n")
+	}
+
+	names := make([]string, 0, len(targets)+1)
+	width := 10
+	if withHelp {
+		names = append(names, "help")
+	}
+	for _, target := range targets {
+		names = append(names, target.Name)
+		if len(target.Name)+1 > width {
+			width = len(target.Name) + 1
+		}
+	}
+	fmt.Fprintf(&b, ".PHONY: %s\n", strings.Join(names, " "))
+
+	if withHelp {
+		b.WriteString("\nhelp:\n")
+		b.WriteString("\t@echo \"Available commands:\"\n")
+		for _, target := range targets {
+			fmt.Fprintf(&b, "\t@echo \"  make %-*s- %s\"\n", width, target.Name, target.Help)
+		}
+	}
+
+	for _, target := range targets {
+		fmt.Fprintf(&b, "\n%s:\n", target.Name)
+		for _, line := range target.Recipe {
+			fmt.Fprintf(&b, "\t%s\n", line)
+		}
+	}
+
+	return b.String()
+}
+
+var makeRuleRe = regexp.MustCompile(`^([^\s:#=][^:#=]*?)\s*::?(?:[^=]|$)`)
+
+// definedMakeTargets returns the names of the rules defined in a Makefile,
+// ignoring special targets like .PHONY and variable assignments.
+func definedMakeTargets(content string) map[string]bool {
+	defined := make(map[string]bool)
+
+	for _, line := range strings.Split(content, "\n") {
+		match := makeRuleRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		for _, name := range strings.Fields(match[1]) {
+			if !strings.HasPrefix(name, ".") {
+				defined[name] = true
+			}
+		}
+	}
+
+	return defined
+}
@@ -0,0 +1,234 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// ManifestFile records every file cc generated along with a hash of what it
+// wrote, so uninstall can tell generated files from ones the user has edited.
+const ManifestFile = ".claude/.cc-manifest.json"
+
+type Manifest struct {
+	Version int                      `json:"version"`
+	Files   map[string]ManifestEntry `json:"files"`
+	// Project is what the files were last generated from
+	Project *ManifestProject `json:"project,omitempty"`
+}
+
+// ManifestProject records the settings a project was generated with, so that
+// cc add can generate it again with more add-ons.
+type ManifestProject struct {
+	Name           string       `json:"name"`
+	Type           string       `json:"type,omitempty"`
+	Description    string       `json:"description,omitempty"`
+	GitHubUsername string       `json:"github_username,omitempty"`
+	GitHubHost     string       `json:"github_host,omitempty"`
+	GitHubRepo     string       `json:"github_repo,omitempty"`
+	Module         string       `json:"module,omitempty"`
+	IssueTemplates string       `json:"issue_templates,omitempty"`
+	ClaudeActions  bool         `json:"claude_actions,omitempty"`
+	Release        bool         `json:"release,omitempty"`
+	CIMatrix       []MatrixAxis `json:"ci_matrix,omitempty"`
+	AddOns         []string     `json:"addons,omitempty"`
+	DenySecrets    bool         `json:"deny_secrets,omitempty"`
+}
+
+func manifestProject(config *ProjectConfig) *ManifestProject {
+	project := &ManifestProject{
+		Name:           config.Name,
+		Type:           config.Type,
+		Description:    config.Description,
+		GitHubUsername: config.GitHubUsername,
+		GitHubHost:     config.GitHubHost,
+		GitHubRepo:     config.GitHubRepo,
+		Module:         config.Module,
+		IssueTemplates: config.IssueTemplates,
+		ClaudeActions:  config.ClaudeActions,
+		Release:        config.Release,
+		CIMatrix:       config.CIMatrix,
+		AddOns:         config.AddOns,
+		DenySecrets:    config.DenySecrets,
+	}
+	// Match what an omitted field loads as
+	if len(project.CIMatrix) == 0 {
+		project.CIMatrix = nil
+	}
+	if len(project.AddOns) == 0 {
+		project.AddOns = nil
+	}
+	return project
+}
+
+// config returns the configuration to generate the project again.
+func (p *ManifestProject) config(root string) *ProjectConfig {
+	return &ProjectConfig{
+		Root:           root,
+		Name:           p.Name,
+		Type:           p.Type,
+		Description:    p.Description,
+		GitHubUsername: p.GitHubUsername,
+		GitHubHost:     p.GitHubHost,
+		GitHubRepo:     p.GitHubRepo,
+		Module:         p.Module,
+		IssueTemplates: p.IssueTemplates,
+		ClaudeActions:  p.ClaudeActions,
+		Release:        p.Release,
+		CIMatrix:       p.CIMatrix,
+		AddOns:         p.AddOns,
+		DenySecrets:    p.DenySecrets,
+		Integration:    true,
+	}
+}
+
+// ManifestEntry describes one generated file. For merged files Block is set
+// and the hash covers only the cc managed block.
+type ManifestEntry struct {
+	SHA256 string `json:"sha256"`
+	Block  bool   `json:"block,omitempty"`
+}
+
+func (g *Generator) LoadManifest(root string) (*Manifest, error) {
+	manifest := &Manifest{Version: 1, Files: make(map[string]ManifestEntry)}
+
+	data, err := g.FS.ReadFile(filepath.Join(root, ManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestFile, err)
+	}
+	if manifest.Files == nil {
+		manifest.Files = make(map[string]ManifestEntry)
+	}
+
+	return manifest, nil
+}
+
+func (m *Manifest) marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// recordProject stores the settings of the run in the manifest.
+func (g *Generator) recordProject(config *ProjectConfig) {
+	project := manifestProject(config)
+	if reflect.DeepEqual(g.manifest.Project, project) {
+		return
+	}
+
+	g.manifest.Project = project
+	g.manifestChanged = true
+}
+
+// generatedUnchanged reports whether rel is a whole file cc generated that
+// still has the content cc wrote.
+func (g *Generator) generatedUnchanged(rel, content string) bool {
+	if g.manifest == nil {
+		return false
+	}
+	entry, ok := g.manifest.Files[rel]
+	return ok && !entry.Block && entry.SHA256 == hashContent(content)
+}
+
+// saveManifest writes the manifest if this run generated anything.
+func (g *Generator) saveManifest() error {
+	if !g.manifestChanged {
+		return nil
+	}
+
+	data, err := g.manifest.marshal()
+	if err != nil {
+		return err
+	}
+
+	return g.replaceFile(filepath.Join(g.root, ManifestFile), data)
+}
+
+// States reported by CheckManifest.
+const (
+	StateUnchanged = "unchanged"
+	StateModified  = "modified"
+	StateMissing   = "missing"
+)
+
+type FileStatus struct {
+	Path  string
+	State string
+}
+
+// CheckManifest compares every generated file with the hash recorded when it
+// was written.
+func (g *Generator) CheckManifest(root string) ([]FileStatus, error) {
+	manifest, err := g.LoadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(manifest.Files))
+	for rel := range manifest.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	statuses := make([]FileStatus, 0, len(paths))
+	for _, rel := range paths {
+		entry := manifest.Files[rel]
+
+		_, current, ok, err := g.readTracked(root, rel, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		state := StateUnchanged
+		switch {
+		case !ok:
+			state = StateMissing
+		case hashContent(current) != entry.SHA256:
+			state = StateModified
+		}
+		statuses = append(statuses, FileStatus{Path: rel, State: state})
+	}
+
+	return statuses, nil
+}
+
+// readTracked returns the full content of a generated file and the part of it
+// cc owns: the whole file, or just the managed block for merged files. ok is
+// false if the file or its block no longer exists.
+func (g *Generator) readTracked(root, rel string, entry ManifestEntry) (content, owned string, ok bool, err error) {
+	data, err := g.FS.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+	content = string(data)
+
+	if !entry.Block {
+		return content, content, true, nil
+	}
+
+	owned, ok = extractBlock(content)
+	return content, owned, ok, nil
+}
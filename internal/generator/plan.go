@@ -0,0 +1,407 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// File actions recorded in a plan.
+const (
+	ActionCreated   = "created"
+	ActionReplaced  = "replaced"
+	ActionMerged    = "merged"
+	ActionSkipped   = "skipped"
+	ActionUnchanged = "unchanged"
+)
+
+// Plan is the set of changes a run would make, computed against the current
+// state of the filesystem. Nothing is written until the plan is applied.
+type Plan struct {
+	Root   string
+	Config ProjectConfig
+	Files  []PlannedFile
+	// Findings are possible secrets in the planned files and in the files
+	// CLAUDE.md refers to
+	Findings []Finding
+	// CredentialFiles are the credential files, such as .env, in the project
+	CredentialFiles []string
+}
+
+// PlannedFile is a single file in a plan. Path is relative to the project root
+// and slash separated; Content is the complete file as it will be written.
+type PlannedFile struct {
+	Path    string
+	Action  string
+	Content []byte
+	// Block is set for merged files, where cc owns only the managed block
+	Block bool
+
+	owned    string
+	original string // hash of the file when planned, empty if it did not exist
+	// untracked is set for files cc changed but does not own, so that they
+	// are neither refreshed nor removed later
+	untracked bool
+}
+
+// Plan computes the files InitializeProject would write for config without
+// touching the filesystem.
+func (g *Generator) Plan(ctx context.Context, config *ProjectConfig) (*Plan, error) {
+	root := config.Root
+	if root == "" {
+		root = "."
+	}
+
+	// Fill in what the git remote tells us without changing the caller's config
+	resolved := *config
+	g.resolveRemote(root, &resolved)
+	config = &resolved
+	if config.GitHub && config.GitHubUsername == "" {
+		return nil, fmt.Errorf("GitHub integration needs an owner: %s has no GitHub remote to take it from", root)
+	}
+
+	g.config = config
+	g.root = root
+	g.plan = &Plan{Root: root, Config: *config}
+	g.planned = make(map[string]int)
+	g.manifest = nil
+	if config.Refresh {
+		manifest, err := g.LoadManifest(root)
+		if err != nil {
+			return nil, err
+		}
+		g.manifest = manifest
+	}
+
+	credentials, err := g.credentialFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	g.plan.CredentialFiles = credentials
+
+	if err := g.generate(ctx, root, config); err != nil {
+		return nil, err
+	}
+
+	if err := g.scanPlan(root); err != nil {
+		return nil, fmt.Errorf("failed to scan for secrets: %w", err)
+	}
+
+	return g.plan, nil
+}
+
+// Apply writes a plan. Files that changed on disk since the plan was made are
+// refused rather than clobbered. If anything fails, every change made so far
+// is rolled back.
+func (g *Generator) Apply(ctx context.Context, plan *Plan) (err error) {
+	g.root = plan.Root
+	g.config = &plan.Config
+	g.journal = newJournal(g.FS)
+	g.run = newRun(g.FS, g.root, g.Now())
+	if g.manifest, err = g.LoadManifest(g.root); err != nil {
+		return err
+	}
+	g.manifestChanged = false
+	defer func() {
+		if err != nil {
+			if rbErr := g.journal.rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	g.recordProject(&plan.Config)
+
+	for _, file := range plan.Files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch file.Action {
+		case ActionSkipped:
+			continue
+		case ActionUnchanged:
+			if !file.untracked {
+				g.track(file.Path, file.owned, file.Block)
+			}
+			continue
+		}
+
+		path := filepath.Join(g.root, filepath.FromSlash(file.Path))
+
+		disk, exists, err := g.readDisk(path)
+		if err != nil {
+			return err
+		}
+		if (exists && hashContent(disk) != file.original) || (!exists && file.original != "") {
+			return fmt.Errorf("%s changed since the plan was made", file.Path)
+		}
+
+		if err := g.replaceFile(path, file.Content); err != nil {
+			return err
+		}
+		if !file.untracked {
+			g.track(file.Path, file.owned, file.Block)
+		}
+	}
+
+	if err := g.saveManifest(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	// Record the run so it can be reverted with cc undo
+	if err := g.saveRun(); err != nil {
+		return fmt.Errorf("failed to record run: %w", err)
+	}
+
+	return nil
+}
+
+// writeFile plans a generated file. Existing files with different content are
+// left alone unless Overwrite is set, or Refresh is set and cc generated them.
+func (g *Generator) writeFile(path, content string) error {
+	// Files that already match are reported as unchanged, so skipped always
+	// means the existing file differs from what cc would write
+	if disk, exists, err := g.readDisk(path); err != nil {
+		return err
+	} else if exists && disk != content && !g.config.Overwrite && !(g.config.Refresh && g.refreshable(path, disk)) {
+		if g.config.Verbose {
+			fmt.Fprintf(g.Log, "Skipping existing file: %s\n", path)
+		}
+		return g.skipFile(path)
+	}
+
+	return g.addFile(path, content, content, false)
+}
+
+// writeBlock plans merging block into path as a cc managed block, keeping
+// whatever else the file contains. With Overwrite the file is replaced by the
+// block.
+func (g *Generator) writeBlock(path, block string) error {
+	current, _, err := g.readCurrent(path)
+	if err != nil {
+		return err
+	}
+
+	content := wrapBlock(block)
+	if !g.config.Overwrite {
+		content = mergeBlock(current, block)
+	}
+
+	inner, _ := extractBlock(content)
+	return g.addFile(path, content, inner, true)
+}
+
+// mergeFile plans content, merged from an existing file cc does not own, for
+// path. The file stays the user's: it is not recorded in the manifest.
+func (g *Generator) mergeFile(path, content string) error {
+	if err := g.addFile(path, content, content, false); err != nil {
+		return err
+	}
+	rel, err := g.rel(path)
+	if err != nil {
+		return err
+	}
+	file := &g.plan.Files[g.planned[rel]]
+	file.untracked = true
+	if file.Action == ActionReplaced {
+		file.Action = ActionMerged
+	}
+	return nil
+}
+
+func (g *Generator) addFile(path, content, owned string, block bool) error {
+	rel, err := g.rel(path)
+	if err != nil {
+		return err
+	}
+
+	disk, exists, err := g.readDisk(path)
+	if err != nil {
+		return err
+	}
+
+	file := PlannedFile{Path: rel, Content: []byte(content), Block: block, owned: owned}
+	switch {
+	case !exists:
+		file.Action = ActionCreated
+	case disk == content:
+		file.Action = ActionUnchanged
+	case block && !g.config.Overwrite:
+		file.Action = ActionMerged
+	default:
+		file.Action = ActionReplaced
+	}
+	if exists {
+		file.original = hashContent(disk)
+	}
+
+	if i, ok := g.planned[rel]; ok {
+		g.plan.Files[i] = file
+		return nil
+	}
+	g.planned[rel] = len(g.plan.Files)
+	g.plan.Files = append(g.plan.Files, file)
+
+	return nil
+}
+
+// refreshable reports whether path holds a file cc generated that has not
+// been modified since.
+func (g *Generator) refreshable(path, content string) bool {
+	rel, err := g.rel(path)
+	return err == nil && g.generatedUnchanged(rel, content)
+}
+
+func (g *Generator) skipFile(path string) error {
+	rel, err := g.rel(path)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := g.planned[rel]; ok {
+		return nil
+	}
+	g.planned[rel] = len(g.plan.Files)
+	g.plan.Files = append(g.plan.Files, PlannedFile{Path: rel, Action: ActionSkipped})
+
+	return nil
+}
+
+func (g *Generator) rel(path string) (string, error) {
+	rel, err := filepath.Rel(g.root, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// readCurrent returns the content path will have once the plan so far is
+// applied.
+func (g *Generator) readCurrent(path string) (string, bool, error) {
+	rel, err := g.rel(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	if i, ok := g.planned[rel]; ok && g.plan.Files[i].Action != ActionSkipped {
+		return string(g.plan.Files[i].Content), true, nil
+	}
+
+	return g.readDisk(path)
+}
+
+// readDisk returns the content of path as it is on the filesystem now.
+func (g *Generator) readDisk(path string) (string, bool, error) {
+	data, err := g.FS.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return string(data), true, nil
+}
+
+// runGenerator plans the files returned by a project type or registered
+// generator.
+func (g *Generator) runGenerator(ctx context.Context, projectPath string, config *ProjectConfig, generate FileGenerator) error {
+	files, err := generate(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		rel := filepath.Clean(filepath.FromSlash(file.Path))
+		if filepath.IsAbs(rel) || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("generated file %q must be inside the project", file.Path)
+		}
+
+		g.generated[filepath.ToSlash(rel)] = true
+		path := filepath.Join(projectPath, rel)
+		if file.Merge {
+			err = g.writeBlock(path, file.Content)
+		} else {
+			err = g.writeFile(path, file.Content)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceFile writes content to path, backing up the original if there is one.
+func (g *Generator) replaceFile(path string, content []byte) error {
+	if _, err := g.FS.Stat(path); err == nil {
+		if err := g.backupFile(path); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+
+	return g.put(path, content)
+}
+
+func (g *Generator) track(rel, owned string, block bool) {
+	entry := ManifestEntry{SHA256: hashContent(owned), Block: block}
+	if g.manifest.Files[rel] == entry {
+		return
+	}
+
+	g.manifest.Files[rel] = entry
+	g.manifestChanged = true
+}
+
+// put atomically writes content to path, recording the change in the journal
+// so it can be rolled back.
+func (g *Generator) put(path string, content []byte) error {
+	if err := g.mkdirAll(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+
+	if err := g.journal.recordFile(path); err != nil {
+		return err
+	}
+
+	mode := fs.FileMode(0644)
+	if info, err := g.FS.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := g.FS.WriteFile(path, content, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// mkdirAll creates path and any missing parents, journaling each directory it
+// creates so rollback can remove them again.
+func (g *Generator) mkdirAll(path string) error {
+	var missing []string
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		if _, err := g.FS.Stat(dir); err == nil {
+			break
+		}
+		missing = append(missing, dir)
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := g.FS.Mkdir(missing[i], 0755); err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				continue
+			}
+			return err
+		}
+		g.journal.recordDir(missing[i])
+	}
+
+	return nil
+}
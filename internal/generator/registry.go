@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// File is a file produced by a FileGenerator. Path is relative to the project
+// root and slash separated.
+type File struct {
+	Path    string
+	Content string
+	// Merge writes Content as a cc managed block instead of replacing the file
+	Merge bool
+}
+
+// FileGenerator produces extra files for a project. Generators run after the
+// built-in Claude Code files, so they may replace any of them.
+type FileGenerator func(ctx context.Context, config *ProjectConfig) ([]File, error)
+
+// ProjectType adds files for a particular kind of project on top of the
+// Claude Code baseline.
+type ProjectType struct {
+	Name        string
+	Description string
+	// IssueFields are added to the bug report issue form
+	IssueFields []IssueField
+	// Stacks are set up in CI even before their files exist
+	Stacks []string
+	// MakeTargets replace the generic targets of the same name; the others
+	// are added after them
+	MakeTargets []MakeTarget
+	// MakeVars are variable assignments (ENV ?= dev) at the top of the
+	// Makefile
+	MakeVars []string
+	// GitIgnore patterns are added to .gitignore
+	GitIgnore []string
+	// PreCommit is YAML for extra repos entries in .pre-commit-config.yaml
+	PreCommit string
+	// CIJobs is YAML for extra jobs entries in the CI workflow
+	CIJobs string
+	// Permissions are written to .claude/settings.json
+	Permissions Permissions
+	// Claude is markdown added to CLAUDE.md before Getting Started
+	Claude     string
+	Generators []FileGenerator
+}
+
+var (
+	registryMu   sync.RWMutex
+	projectTypes = make(map[string]ProjectType)
+)
+
+// RegisterProjectType makes a project type available to every Generator.
+// Built-in types register themselves from init.
+func RegisterProjectType(t ProjectType) error {
+	if t.Name == "" {
+		return fmt.Errorf("project type must have a name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := projectTypes[t.Name]; ok {
+		return fmt.Errorf("project type %q is already registered", t.Name)
+	}
+	projectTypes[t.Name] = t
+
+	return nil
+}
+
+// ProjectTypes returns the globally registered project types sorted by name.
+func ProjectTypes() []ProjectType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]ProjectType, 0, len(projectTypes))
+	for _, t := range projectTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+
+	return types
+}
+
+// RegisterProjectType makes a project type available to this Generator only.
+// It may shadow a globally registered type of the same name.
+func (g *Generator) RegisterProjectType(t ProjectType) error {
+	if t.Name == "" {
+		return fmt.Errorf("project type must have a name")
+	}
+	if _, ok := g.types[t.Name]; ok {
+		return fmt.Errorf("project type %q is already registered", t.Name)
+	}
+
+	if g.types == nil {
+		g.types = make(map[string]ProjectType)
+	}
+	g.types[t.Name] = t
+
+	return nil
+}
+
+// RegisterGenerator adds a generator that runs for every project.
+func (g *Generator) RegisterGenerator(generate FileGenerator) {
+	g.generators = append(g.generators, generate)
+}
+
+// projectType looks up a type by name. The empty name is the generic project,
+// which only gets the Claude Code baseline.
+func (g *Generator) projectType(name string) (ProjectType, error) {
+	if name == "" {
+		return ProjectType{}, nil
+	}
+	if t, ok := g.types[name]; ok {
+		return t, nil
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if t, ok := projectTypes[name]; ok {
+		return t, nil
+	}
+
+	return ProjectType{}, fmt.Errorf("unknown project type %q", name)
+}
+
+// mustRegisterProjectType registers a built-in type, panicking on failure.
+func mustRegisterProjectType(t ProjectType) {
+	if err := RegisterProjectType(t); err != nil {
+		panic(err)
+	}
+}
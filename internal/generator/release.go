@@ -0,0 +1,244 @@
+package generator
+
+import (
+	"path/filepath"
+)
+
+// Release tools are run by version rather than through actions, so nothing
+// outside the pinned action table runs in the release workflow.
+const (
+	gitCliffVersion   = "2.8.0"
+	goreleaserVersion = "v2.8.2"
+)
+
+func releaseMakeTargets() []MakeTarget {
+	return []MakeTarget{
+		{
+			Name: "changelog",
+			Help: "Update CHANGELOG.md from conventional commits (needs git-cliff)",
+			Recipe: []string{
+				`git cliff --output CHANGELOG.md`,
+			},
+		},
+		{
+			Name: "release",
+			Help: "Tag a release, e.g. make release VERSION=v1.2.3",
+			Recipe: []string{
+				`@test -n "$(VERSION)" || (echo "Usage: make release VERSION=v1.2.3"; exit 1)`,
+				`@test -z "$$(git status --porcelain)" || (echo "Working tree is not clean"; exit 1)`,
+				`git cliff --tag $(VERSION) --output CHANGELOG.md`,
+				`git add CHANGELOG.md`,
+				`git commit -m "chore(release): $(VERSION)"`,
+				`git tag -a $(VERSION) -m "Release $(VERSION)"`,
+				`@echo "Push the release with: git push --follow-tags"`,
+			},
+		},
+	}
+}
+
+// generateRelease adds changelog configuration, a tag-triggered release
+// workflow, GoReleaser for Go projects and a /release slash command.
+func (g *Generator) generateRelease(projectPath string, config *ProjectConfig) error {
+	stacks, err := g.detectStacks(projectPath)
+	if err != nil {
+		return err
+	}
+	goProject := false
+	for _, stack := range stacks {
+		if stack == StackGo {
+			goProject = true
+		}
+	}
+
+	if err := g.generateCliffConfig(projectPath); err != nil {
+		return err
+	}
+
+	if goProject {
+		if err := g.generateGoReleaserConfig(projectPath, config); err != nil {
+			return err
+		}
+	}
+
+	if err := g.generateReleaseWorkflow(projectPath, goProject); err != nil {
+		return err
+	}
+
+	return g.generateReleaseCommand(projectPath, goProject)
+}
+
+func (g *Generator) generateCliffConfig(projectPath string) error {
+	content := `# git-cliff configuration: https://git-cliff.org/docs/configuration
+# Generated by cc - Claude Code optimization tool
+#
+# Builds CHANGELOG.md from conventional commits (feat:, fix:, docs: ...).
+
+[changelog]
+header = """
+# Changelog
+
+All notable changes to this project are documented in this file.
+"""
+body = """
+{% if version %}\
+## [{{ version | trim_start_matches(pat="v") }}] - {{ timestamp | date(format="%Y-%m-%d") }}
+{% else %}\
+## [Unreleased]
+{% endif %}\
+{% for group, commits in commits | group_by(attribute="group") %}
+### {{ group | striptags | trim | upper_first }}
+{% for commit in commits %}
+- {% if commit.scope %}**{{ commit.scope }}:** {% endif %}{{ commit.message | upper_first }}\
+{% if commit.breaking %} (**breaking**){% endif %}\
+{% endfor %}
+{% endfor %}
+"""
+trim = true
+
+[git]
+conventional_commits = true
+filter_unconventional = true
+split_commits = false
+commit_parsers = [
+  { message = "^feat", group = "<!-- 0 -->Features" },
+  { message = "^fix", group = "<!-- 1 -->Bug Fixes" },
+  { message = "^perf", group = "<!-- 2 -->Performance" },
+  { message = "^refactor", group = "<!-- 3 -->Refactoring" },
+  { message = "^docs", group = "<!-- 4 -->Documentation" },
+  { message = "^test", group = "<!-- 5 -->Tests" },
+  { message = "^chore\\(release\\)", skip = true },
+  { message = "^(chore|ci|build|style)", group = "<!-- 6 -->Maintenance" },
+]
+protect_breaking_commits = true
+tag_pattern = "v[0-9].*"
+sort_commits = "oldest"
+`
+
+	return g.writeFile(filepath.Join(projectPath, "cliff.toml"), content)
+}
+
+func (g *Generator) generateGoReleaserConfig(projectPath string, config *ProjectConfig) error {
+	// Build the command named after the project if there is one. The go
+	// type's scaffold has one but is planned after this.
+	main := "."
+	if g.ptype.Name == "go" || g.exists(filepath.Join(projectPath, "cmd", config.Name, "main.go")) {
+		main = "./cmd/" + config.Name
+	}
+
+	content := `# GoReleaser configuration: https://goreleaser.com/customization/
+# Generated by cc - Claude Code optimization tool
+version: 2
+
+before:
+  hooks:
+    - go mod tidy
+
+builds:
+  - main: ` + main + `
+    binary: ` + config.Name + `
+    env:
+      - CGO_ENABLED=0
+    goos: [linux, darwin, windows]
+    goarch: [amd64, arm64]
+    flags:
+      - -trimpath
+    ldflags:
+      - -s -w -X main.version={{.Version}}
+
+archives:
+  - formats: [tar.gz]
+    format_overrides:
+      - goos: windows
+        formats: [zip]
+
+checksum:
+  name_template: checksums.txt
+
+# Release notes come from git-cliff (see cliff.toml)
+changelog:
+  disable: true
+`
+
+	return g.writeFile(filepath.Join(projectPath, ".goreleaser.yaml"), content)
+}
+
+func (g *Generator) generateReleaseWorkflow(projectPath string, goProject bool) error {
+	publish := `      - name: Publish release
+        env:
+          GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+        run: gh release create "$GITHUB_REF_NAME" --title "$GITHUB_REF_NAME" --notes-file RELEASE_NOTES.md --verify-tag
+`
+	if goProject {
+		publish = `      - uses: ` + uses("actions/setup-go") + `
+        with:
+          go-version-file: go.mod
+          cache: true
+
+      - name: Publish release with GoReleaser
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+        run: go run github.com/goreleaser/goreleaser/v2@` + goreleaserVersion + ` release --clean --release-notes RELEASE_NOTES.md
+`
+	}
+
+	content := `name: Release
+
+on:
+  push:
+    tags: ["v*"]
+
+permissions:
+  contents: write
+
+concurrency:
+  group: release-${{ github.ref }}
+  cancel-in-progress: false
+
+jobs:
+  release:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: ` + uses("actions/checkout") + `
+        with:
+          fetch-depth: 0
+          persist-credentials: false
+
+      - name: Generate release notes
+        run: pipx run git-cliff==` + gitCliffVersion + ` --latest --strip header --output RELEASE_NOTES.md
+
+` + publish
+
+	return g.writeFile(filepath.Join(projectPath, ".github", "workflows", "release.yml"), content)
+}
+
+func (g *Generator) generateReleaseCommand(projectPath string, goProject bool) error {
+	check := ""
+	if goProject {
+		check = "\n   Also run `go run github.com/goreleaser/goreleaser/v2@" + goreleaserVersion + " check` to validate `.goreleaser.yaml`."
+	}
+
+	content := `---
+description: Prepare and tag a new release
+argument-hint: [version, e.g. v1.2.3]
+---
+
+Prepare a release of this project. The requested version is: $ARGUMENTS
+
+1. Check that the working tree is clean and the current branch is main and up
+   to date with the remote. Stop and tell me if it is not.
+2. Run ` + "`make test`" + ` and ` + "`make lint`" + `. Stop and report any failures.` + check + `
+3. If no version was given, propose one from the commits since the last tag
+   using semantic versioning: a breaking change bumps the major version, a
+   ` + "`feat`" + ` commit the minor version, anything else the patch version.
+   ` + "`git cliff --bumped-version`" + ` computes this.
+4. Show me the changelog entry with ` + "`git cliff --unreleased --tag <version>`" + `
+   and wait for my confirmation.
+5. Run ` + "`make release VERSION=<version>`" + ` to update CHANGELOG.md, commit
+   and create the tag.
+6. Ask before pushing. When I confirm, run ` + "`git push --follow-tags`" + `.
+   The tag triggers ` + "`.github/workflows/release.yml`" + `, which publishes
+   the release.
+`
+
+	return g.writeFile(filepath.Join(projectPath, ".claude", "commands", "release.md"), content)
+}
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"bufio"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultGitHubHost is used in generated URLs when no host is configured or
+// detected.
+const DefaultGitHubHost = "github.com"
+
+// Remote is a repository location parsed from a git remote URL. Owner may
+// contain slashes for nested groups.
+type Remote struct {
+	Host  string
+	Owner string
+	Repo  string
+}
+
+// ParseRemoteURL parses the SSH (git@host:owner/repo.git, ssh://...) and
+// HTTPS forms of a git remote URL.
+func ParseRemoteURL(raw string) (Remote, bool) {
+	raw = strings.TrimSpace(raw)
+
+	var host, path string
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return Remote{}, false
+		}
+		host = u.Host
+		// SSH ports say nothing about where the web UI lives
+		if u.Scheme != "http" && u.Scheme != "https" {
+			host = u.Hostname()
+		}
+		path = u.Path
+	} else {
+		// scp-like syntax: [user@]host:path
+		colon := strings.Index(raw, ":")
+		if colon < 0 || strings.Contains(raw[:colon], "/") {
+			return Remote{}, false
+		}
+		host = raw[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		path = raw[colon+1:]
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	parts := strings.Split(path, "/")
+	if host == "" || len(parts) < 2 {
+		return Remote{}, false
+	}
+	for _, part := range parts {
+		if part == "" {
+			return Remote{}, false
+		}
+	}
+
+	return Remote{
+		Host:  strings.ToLower(host),
+		Owner: strings.Join(parts[:len(parts)-1], "/"),
+		Repo:  parts[len(parts)-1],
+	}, true
+}
+
+// DetectRemote finds the git repository containing root and parses its origin
+// remote, or the first remote if there is no origin.
+func (g *Generator) DetectRemote(root string) (Remote, bool) {
+	remote, _, ok := g.detectRemote(root)
+	return remote, ok
+}
+
+// detectRemote is DetectRemote that also returns the working tree the remote
+// belongs to, which may be a parent of root.
+func (g *Generator) detectRemote(root string) (Remote, string, bool) {
+	config, workTree, ok := g.gitConfig(root)
+	if !ok {
+		return Remote{}, "", false
+	}
+
+	var first, origin string
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.TrimSpace(key) != "url" || !strings.HasPrefix(section, "[remote ") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if first == "" {
+			first = value
+		}
+		if section == `[remote "origin"]` {
+			origin = value
+		}
+	}
+
+	if origin == "" {
+		origin = first
+	}
+	remote, ok := ParseRemoteURL(origin)
+	return remote, workTree, ok
+}
+
+// gitConfig returns the config and the working tree directory of the
+// repository containing root, following the .git files used by worktrees and
+// submodules.
+func (g *Generator) gitConfig(root string) (string, string, bool) {
+	dir, err := filepath.Abs(root)
+	if err != nil {
+		return "", "", false
+	}
+
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		if info, err := g.FS.Stat(gitPath); err == nil {
+			gitDir := gitPath
+			if !info.IsDir() {
+				data, err := g.FS.ReadFile(gitPath)
+				if err != nil {
+					return "", "", false
+				}
+				gitDir = strings.TrimSpace(strings.TrimPrefix(string(data), "gitdir:"))
+				if !filepath.IsAbs(gitDir) {
+					gitDir = filepath.Join(dir, gitDir)
+				}
+				// Linked worktrees keep the config in the main repository
+				if common, err := g.FS.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+					commonDir := strings.TrimSpace(string(common))
+					if !filepath.IsAbs(commonDir) {
+						commonDir = filepath.Join(gitDir, commonDir)
+					}
+					gitDir = commonDir
+				}
+			}
+
+			data, err := g.FS.ReadFile(filepath.Join(gitDir, "config"))
+			if err != nil {
+				return "", "", false
+			}
+			return string(data), dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// isGitHubHost reports whether host looks like GitHub or GitHub Enterprise.
+// Enterprise hosts without "github" in their name are recognized when they
+// match the configured host.
+func isGitHubHost(host, configured string) bool {
+	return host == DefaultGitHubHost || strings.Contains(host, "github") || (configured != "" && host == strings.ToLower(configured))
+}
+
+// resolveRemote fills in the GitHub owner, host and repository and the Go
+// module path that config leaves empty, from the git remote when it points at
+// GitHub and otherwise from the defaults. The remote never turns GitHub
+// integration on: it is only consulted for GitHub once config.GitHub or an
+// owner asks for integration.
+func (g *Generator) resolveRemote(root string, config *ProjectConfig) {
+	remote, workTree, found := g.detectRemote(root)
+	if found && (config.GitHub || config.GitHubUsername != "") && isGitHubHost(remote.Host, config.GitHubHost) {
+		if config.GitHubUsername == "" {
+			config.GitHubUsername = remote.Owner
+		}
+		if config.GitHubHost == "" {
+			config.GitHubHost = remote.Host
+		}
+		if config.GitHubRepo == "" {
+			config.GitHubRepo = remote.Repo
+		}
+	}
+
+	if config.GitHubHost == "" {
+		config.GitHubHost = DefaultGitHubHost
+	}
+	if config.GitHubRepo == "" {
+		config.GitHubRepo = config.Name
+	}
+
+	// Any remote, not only GitHub, gives the module its import path
+	if config.Module == "" {
+		switch {
+		case found:
+			config.Module = modulePath(remote.Host, remote.Owner, remote.Repo)
+			// A project below the repository root is a module of its own
+			if abs, err := filepath.Abs(root); err == nil {
+				if rel, err := filepath.Rel(workTree, abs); err == nil && rel != "." {
+					config.Module += "/" + filepath.ToSlash(rel)
+				}
+			}
+		case config.GitHubUsername != "":
+			config.Module = modulePath(config.GitHubHost, config.GitHubUsername, config.GitHubRepo)
+		default:
+			config.Module = config.Name
+		}
+	}
+}
+
+// modulePath returns the Go module path for a repository. Import paths cannot
+// carry a port, so one kept in host for web URLs is dropped.
+func modulePath(host, owner, repo string) string {
+	return (&url.URL{Host: host}).Hostname() + "/" + owner + "/" + repo
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want Remote
+		ok   bool
+	}{
+		{url: "git@github.com:acme/widgets.git", want: Remote{Host: "github.com", Owner: "acme", Repo: "widgets"}, ok: true},
+		{url: "https://github.com/acme/widgets", want: Remote{Host: "github.com", Owner: "acme", Repo: "widgets"}, ok: true},
+		{url: "https://user@GitHub.com/acme/widgets.git/", want: Remote{Host: "github.com", Owner: "acme", Repo: "widgets"}, ok: true},
+		{url: "ssh://git@github.example.com:2222/platform/api.git", want: Remote{Host: "github.example.com", Owner: "platform", Repo: "api"}, ok: true},
+		{url: "https://git.example.com:8443/group/sub/repo.git", want: Remote{Host: "git.example.com:8443", Owner: "group/sub", Repo: "repo"}, ok: true},
+		{url: "github.com:acme/widgets", want: Remote{Host: "github.com", Owner: "acme", Repo: "widgets"}, ok: true},
+		{url: "/srv/git/widgets.git", ok: false},
+		{url: "git@github.com:widgets.git", ok: false},
+		{url: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, ok := ParseRemoteURL(tt.url)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseRemoteURL(%q) = %+v, %v, want %+v, %v", tt.url, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestResolveRemote(t *testing.T) {
+	const config = "[core]\n\tbare = false\n[remote \"upstream\"]\n\turl = git@github.com:other/fork.git\n[remote \"origin\"]\n\turl = https://github.example.com/acme/widgets.git\n"
+
+	tests := []struct {
+		name   string
+		files  map[string]string
+		config ProjectConfig
+		want   ProjectConfig
+	}{
+		{
+			name:   "no remote",
+			config: ProjectConfig{Name: "example"},
+			want:   ProjectConfig{Name: "example", GitHubHost: "github.com", GitHubRepo: "example", Module: "example"},
+		},
+		{
+			name:   "origin",
+			files:  map[string]string{".git/config": config},
+			config: ProjectConfig{Name: "example"},
+			want:   ProjectConfig{Name: "example", GitHubHost: "github.com", GitHubRepo: "example", Module: "github.example.com/acme/widgets"},
+		},
+		{
+			name:   "owner from origin",
+			files:  map[string]string{".git/config": config},
+			config: ProjectConfig{Name: "example", GitHub: true},
+			want:   ProjectConfig{Name: "example", GitHub: true, GitHubUsername: "acme", GitHubHost: "github.example.com", GitHubRepo: "widgets", Module: "github.example.com/acme/widgets"},
+		},
+		{
+			name:   "origin with github flag",
+			files:  map[string]string{".git/config": config},
+			config: ProjectConfig{Name: "example", GitHubUsername: "acme"},
+			want:   ProjectConfig{Name: "example", GitHubUsername: "acme", GitHubHost: "github.example.com", GitHubRepo: "widgets", Module: "github.example.com/acme/widgets"},
+		},
+		{
+			name:   "flags override",
+			files:  map[string]string{".git/config": config},
+			config: ProjectConfig{Name: "example", GitHubUsername: "octocat", GitHubRepo: "gadgets", Module: "example.com/widgets"},
+			want:   ProjectConfig{Name: "example", GitHubUsername: "octocat", GitHubHost: "github.example.com", GitHubRepo: "gadgets", Module: "example.com/widgets"},
+		},
+		{
+			name:   "not github",
+			files:  map[string]string{".git/config": "[remote \"origin\"]\n\turl = git@gitlab.com:acme/widgets.git\n"},
+			config: ProjectConfig{Name: "example"},
+			want:   ProjectConfig{Name: "example", GitHubHost: "github.com", GitHubRepo: "example", Module: "gitlab.com/acme/widgets"},
+		},
+		{
+			name:   "nested",
+			files:  map[string]string{"../.git/config": config},
+			config: ProjectConfig{Name: "example"},
+			want:   ProjectConfig{Name: "example", GitHubHost: "github.com", GitHubRepo: "example", Module: "github.example.com/acme/widgets/project"},
+		},
+		{
+			name:   "port",
+			files:  map[string]string{".git/config": "[remote \"origin\"]\n\turl = https://github.example.com:8443/acme/widgets.git\n"},
+			config: ProjectConfig{Name: "example", GitHubUsername: "acme"},
+			want:   ProjectConfig{Name: "example", GitHubUsername: "acme", GitHubHost: "github.example.com:8443", GitHubRepo: "widgets", Module: "github.example.com/acme/widgets"},
+		},
+		{
+			name:   "github flag without remote",
+			config: ProjectConfig{Name: "example", GitHubUsername: "octocat"},
+			want:   ProjectConfig{Name: "example", GitHubUsername: "octocat", GitHubHost: "github.com", GitHubRepo: "example", Module: "github.com/octocat/example"},
+		},
+		{
+			name:   "worktree",
+			files:  map[string]string{".git": "gitdir: /main/.git/worktrees/project\n", "../main/.git/worktrees/project/commondir": "../..\n", "../main/.git/config": config},
+			config: ProjectConfig{Name: "example", GitHubUsername: "acme"},
+			want:   ProjectConfig{Name: "example", GitHubUsername: "acme", GitHubHost: "github.example.com", GitHubRepo: "widgets", Module: "github.example.com/acme/widgets"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := newTestGenerator(AferoFS{Fs: newMemFS(t, tt.files)})
+
+			got := tt.config
+			gen.resolveRemote(testRoot, &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveRemote() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGitHubRemoteDoesNotEnableIntegration(t *testing.T) {
+	mem := newMemFS(t, map[string]string{".git/config": "[remote \"origin\"]\n\turl = git@github.com:acme/widgets.git\n"})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "widgets"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"LICENSE", "CONTRIBUTING.md", ".github/CODEOWNERS"} {
+		if exists, _ := afero.Exists(mem, filepath.Join(testRoot, path)); exists {
+			t.Errorf("%s created without --github", path)
+		}
+	}
+}
+
+func TestGitHubIntegrationNeedsOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		config ProjectConfig
+		owner  string
+	}{
+		{
+			name:   "inferred",
+			files:  map[string]string{".git/config": "[remote \"origin\"]\n\turl = git@github.com:acme/widgets.git\n"},
+			config: ProjectConfig{Root: testRoot, Name: "widgets", GitHub: true},
+			owner:  "acme",
+		},
+		{
+			name:   "explicit",
+			files:  map[string]string{".git/config": "[remote \"origin\"]\n\turl = git@github.com:acme/widgets.git\n"},
+			config: ProjectConfig{Root: testRoot, Name: "widgets", GitHub: true, GitHubUsername: "octocat"},
+			owner:  "octocat",
+		},
+		{
+			name:   "no remote",
+			config: ProjectConfig{Root: testRoot, Name: "widgets", GitHub: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := newTestGenerator(AferoFS{Fs: newMemFS(t, tt.files)})
+
+			plan, err := gen.Plan(t.Context(), &tt.config)
+			if tt.owner == "" {
+				if err == nil {
+					t.Fatal("Plan succeeded without an owner")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.Config.GitHubUsername != tt.owner {
+				t.Errorf("owner = %q, want %q", plan.Config.GitHubUsername, tt.owner)
+			}
+			var license bool
+			for _, file := range plan.Files {
+				license = license || file.Path == "LICENSE"
+			}
+			if !license {
+				t.Error("no LICENSE planned with GitHub integration")
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding is a possible secret found by the scanner. Path is relative to the
+// project root and slash separated; Line is 0 for findings about a whole file.
+type Finding struct {
+	Path string
+	Line int
+	Rule string
+	// Secret is the matched value with all but its first characters masked
+	Secret string
+}
+
+// ScanResult is what Scan found in a project.
+type ScanResult struct {
+	Findings []Finding
+	// CredentialFiles are files such as .env or private keys that should
+	// never be committed or read by Claude, whether or not they are ignored
+	CredentialFiles []string
+}
+
+// ScanIgnoreComment on a line stops the scanner reporting it.
+const ScanIgnoreComment = "cc:ignore-secret"
+
+// maxScanSize is the largest file the scanner reads.
+const maxScanSize = 1 << 20
+
+// secretRule matches a token format. Rules with an entropy threshold only
+// report values that look random enough, to skip placeholders and code.
+type secretRule struct {
+	Name string
+	Re   *regexp.Regexp
+	// Group is the submatch holding the secret, 0 for the whole match
+	Group      int
+	MinEntropy float64
+}
+
+var secretRules = []secretRule{
+	{Name: "private-key", Re: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----`)},
+	{Name: "aws-access-key-id", Re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "github-token", Re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{Name: "gitlab-token", Re: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{Name: "anthropic-api-key", Re: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{32,}`)},
+	{Name: "openai-api-key", Re: regexp.MustCompile(`\bsk-(?:proj-[A-Za-z0-9_-]{40,}|[A-Za-z0-9]{48})\b`)},
+	{Name: "slack-token", Re: regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{Name: "stripe-secret-key", Re: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{20,}`)},
+	{Name: "google-api-key", Re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{
+		Name:       "generic-secret",
+		Re:         regexp.MustCompile(`(?i)[\w.-]*(?:secret|token|passw(?:or)?d|api[_-]?key|access[_-]?key|auth[_-]?key|credential)[\w.-]*["']?\s*[:=]\s*["']?([A-Za-z0-9+/_.~=-]{16,})`),
+		Group:      1,
+		MinEntropy: 3.5,
+	},
+}
+
+// credentialFilePatterns match the base names of files that hold credentials.
+var credentialFilePatterns = []string{
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "*.jks", "*.keystore",
+	"id_rsa", "id_dsa", "id_ecdsa", "id_ed25519", ".netrc", ".pypirc",
+	"credentials.json", "*.tfstate", "*.tfstate.*", "kubeconfig",
+}
+
+// credentialFileExamples are templates that are meant to be committed.
+var credentialFileExamples = []string{".env.example", ".env.sample", ".env.template", ".env.dist"}
+
+// scanSkipDirs are dependency, build and state directories the scanner never
+// walks into.
+var scanSkipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+	"venv":         true,
+	"target":       true,
+	".terraform":   true,
+	"__pycache__":  true,
+	".cc-backups":  true,
+}
+
+// isCredentialFile reports whether the file at the slash separated rel path
+// is a credential file by its name.
+func isCredentialFile(rel string) bool {
+	name := path.Base(rel)
+	if contains(credentialFileExamples, name) {
+		return false
+	}
+	for _, pattern := range credentialFilePatterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// scanContent returns the possible secrets in a file's content.
+func scanContent(rel, content string) []Finding {
+	var findings []Finding
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(line, ScanIgnoreComment) {
+			continue
+		}
+		for _, rule := range secretRules {
+			match := rule.Re.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			secret := match[rule.Group]
+			if rule.MinEntropy > 0 && (entropy(secret) < rule.MinEntropy || !mixedCharacters(secret)) {
+				continue
+			}
+			findings = append(findings, Finding{Path: rel, Line: i + 1, Rule: rule.Name, Secret: maskSecret(secret)})
+			// One finding per line is enough to act on
+			break
+		}
+	}
+	return findings
+}
+
+// entropy is the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var bits float64
+	n := float64(len([]rune(s)))
+	for _, count := range counts {
+		p := float64(count) / n
+		bits -= p * math.Log2(p)
+	}
+	return bits
+}
+
+// mixedCharacters reports whether s has both letters and digits, which
+// generated secrets almost always do and identifiers rarely do.
+func mixedCharacters(s string) bool {
+	return strings.ContainsAny(s, "0123456789") && strings.IndexFunc(s, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	}) >= 0
+}
+
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", 8)
+}
+
+// Scan looks for secrets in the files at paths, relative to root and slash
+// separated, or in every file under root if paths is nil. Binary files, large
+// files and dependency directories are skipped. Credential files are always
+// looked for in the whole tree, since ignored ones matter too.
+func (g *Generator) Scan(ctx context.Context, root string, paths []string) (*ScanResult, error) {
+	credentials, err := g.credentialFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		if paths, err = g.projectFiles(ctx, root); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ScanResult{CredentialFiles: credentials}
+
+	for _, rel := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if isCredentialFile(rel) {
+			continue
+		}
+		content, ok, err := g.readScannable(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result.Findings = append(result.Findings, scanContent(rel, content)...)
+		}
+	}
+
+	return result, nil
+}
+
+// readScannable reads a text file for scanning. Missing, binary and large
+// files are reported as not ok.
+func (g *Generator) readScannable(path string) (string, bool, error) {
+	info, err := g.FS.Stat(path)
+	if err != nil || info.IsDir() || info.Size() > maxScanSize {
+		return "", false, nil
+	}
+	content, exists, err := g.readDisk(path)
+	if err != nil || !exists {
+		return "", false, err
+	}
+	if strings.IndexByte(content, 0) >= 0 {
+		return "", false, nil
+	}
+	return content, true, nil
+}
+
+// projectFiles lists every file under root, relative and slash separated,
+// skipping scanSkipDirs.
+func (g *Generator) projectFiles(ctx context.Context, root string) ([]string, error) {
+	var files []string
+	var walk func(dir, rel string) error
+	walk = func(dir, rel string) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		entries, err := g.FS.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			name := path.Join(rel, entry.Name())
+			if entry.IsDir() {
+				if scanSkipDirs[entry.Name()] {
+					continue
+				}
+				if err := walk(filepath.Join(dir, entry.Name()), name); err != nil {
+					return err
+				}
+				continue
+			}
+			files = append(files, name)
+		}
+		return nil
+	}
+	// A new project's directory may not exist yet
+	if err := walk(root, ""); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list project files: %w", err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// claudeReferenceRe matches the targets of markdown links and code spans,
+// where CLAUDE.md names files.
+var claudeReferenceRe = regexp.MustCompile("\\]\\(([^)\\s]+)\\)|`([^`\\s]+)`")
+
+// claudeReferences returns the files in root that a CLAUDE.md refers to.
+func (g *Generator) claudeReferences(root, claudeMD string) []string {
+	var refs []string
+	for _, match := range claudeReferenceRe.FindAllStringSubmatch(claudeMD, -1) {
+		ref := match[1] + match[2]
+		if strings.Contains(ref, "://") || strings.HasPrefix(ref, "#") {
+			continue
+		}
+		ref = path.Clean(strings.TrimPrefix(ref, "./"))
+		if ref == "." || strings.HasPrefix(ref, "../") || path.IsAbs(ref) || contains(refs, ref) {
+			continue
+		}
+		if info, err := g.FS.Stat(filepath.Join(root, filepath.FromSlash(ref))); err == nil && !info.IsDir() {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// scanPlan scans the files in the plan and the files its CLAUDE.md refers
+// to, so that a run warns before it adds a secret or points Claude at one.
+func (g *Generator) scanPlan(root string) error {
+	scanned := make(map[string]bool)
+	for _, file := range g.plan.Files {
+		if file.Action == ActionSkipped {
+			continue
+		}
+		scanned[file.Path] = true
+		g.plan.Findings = append(g.plan.Findings, scanContent(file.Path, string(file.Content))...)
+	}
+
+	claudeMD, _, err := g.readCurrent(filepath.Join(root, "CLAUDE.md"))
+	if err != nil {
+		return err
+	}
+	for _, ref := range g.claudeReferences(root, claudeMD) {
+		if scanned[ref] {
+			continue
+		}
+		if isCredentialFile(ref) {
+			g.plan.Findings = append(g.plan.Findings, Finding{Path: ref, Rule: "credential-file"})
+			continue
+		}
+		content, ok, err := g.readScannable(filepath.Join(root, filepath.FromSlash(ref)))
+		if err != nil {
+			return err
+		}
+		if ok {
+			g.plan.Findings = append(g.plan.Findings, scanContent(ref, content)...)
+		}
+	}
+
+	return nil
+}
+
+// credentialFiles lists the credential files under root.
+func (g *Generator) credentialFiles(ctx context.Context, root string) ([]string, error) {
+	files, err := g.projectFiles(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []string
+	for _, rel := range files {
+		if isCredentialFile(rel) {
+			credentials = append(credentials, rel)
+		}
+	}
+	return credentials, nil
+}
+
+// secretDenyRules are the Claude Code rules that stop it reading credential
+// files: the usual .env files plus the ones found in the project.
+func secretDenyRules(credentialFiles []string) []string {
+	rules := []string{"Read(./.env)", "Read(./.env.local)", "Read(./.env.*.local)"}
+	for _, rel := range credentialFiles {
+		rules = appendUnique(rules, "Read(./"+rel+")")
+	}
+	return rules
+}
+
+// PlanDenySecrets plans adding deny rules for the project's credential files
+// to .claude/settings.json, regenerating the project from its manifest like
+// PlanAddOns.
+func (g *Generator) PlanDenySecrets(ctx context.Context, root string) (*Plan, error) {
+	return g.replan(ctx, root, func(config *ProjectConfig) {
+		config.DenySecrets = true
+	})
+}
@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+)
+
+// Tokens are assembled at run time so this file does not trip secret scanners.
+var (
+	fakeAWSKey    = "AKIA" + "Z7Q2LX4MPN8RT5WB"
+	fakeGitHubPAT = "ghp_" + strings.Repeat("aB3dE5fG7h", 4)
+	fakeAnthropic = "sk-ant-" + "api03-" + strings.Repeat("Xy9Kp2Lm", 5)
+	fakePassword  = "Zq8xL2pV9mK4rT7wY1nB"
+)
+
+func TestScanContent(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		rule string
+	}{
+		{name: "aws key", line: `aws_access_key_id = "` + fakeAWSKey + `"`, rule: "aws-access-key-id"},
+		{name: "github token", line: "GITHUB_TOKEN=" + fakeGitHubPAT, rule: "github-token"},
+		{name: "anthropic key", line: "client = Anthropic(api_key=\"" + fakeAnthropic + "\")", rule: "anthropic-api-key"},
+		{name: "private key", line: "-----BEGIN OPENSSH " + "PRIVATE KEY-----", rule: "private-key"},
+		{name: "random password", line: "DB_PASSWORD=" + fakePassword, rule: "generic-secret"},
+		{name: "yaml token", line: "  auth_token: '" + fakePassword + "'", rule: "generic-secret"},
+		{name: "secret reference", line: "  token: ${{ secrets.GITHUB_TOKEN }}"},
+		{name: "environment lookup", line: `password = os.environ.get("DB_PASSWORD_PRIMARY_REPLICA")`},
+		{name: "placeholder", line: "API_KEY=your-api-key-goes-here"},
+		{name: "short password", line: "POSTGRES_PASSWORD: airflow"},
+		{name: "identifier", line: "tokenizer := newTokenizerForLanguage(cfg)"},
+		{name: "ignored", line: "DB_PASSWORD=" + fakePassword + " # " + ScanIgnoreComment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanContent("file", "first line\n"+tt.line+"\n")
+			if tt.rule == "" {
+				if len(findings) != 0 {
+					t.Fatalf("findings = %+v, want none", findings)
+				}
+				return
+			}
+			if len(findings) != 1 {
+				t.Fatalf("findings = %+v, want one", findings)
+			}
+			if findings[0].Rule != tt.rule || findings[0].Line != 2 {
+				t.Errorf("finding = %+v, want rule %s on line 2", findings[0], tt.rule)
+			}
+			if strings.Contains(tt.line, findings[0].Secret) {
+				t.Errorf("secret %q is not masked", findings[0].Secret)
+			}
+		})
+	}
+}
+
+func TestIsCredentialFile(t *testing.T) {
+	for path, want := range map[string]bool{
+		".env":                        true,
+		"services/api/.env.local":     true,
+		"certs/server.key":            true,
+		"deploy/id_ed25519":           true,
+		"infra/terraform.tfstate":     true,
+		".env.example":                false,
+		"docs/keys.md":                false,
+		"internal/environment/env.go": false,
+	} {
+		if got := isCredentialFile(path); got != want {
+			t.Errorf("isCredentialFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	mem := newMemFS(t, map[string]string{
+		"config.py":                   "KEY = \"" + fakeAWSKey + "\"\n",
+		"README.md":                   "# Example\n",
+		".env":                        "DB_PASSWORD=" + fakePassword + "\n",
+		"certs/tls.pem":               "-----BEGIN RSA " + "PRIVATE KEY-----\n",
+		"node_modules/pkg/index.js":   "token = \"" + fakeGitHubPAT + "\"\n",
+		"assets/logo.png":             "\x00" + fakeGitHubPAT,
+		".claude/.cc-backups/1/x.txt": fakeGitHubPAT,
+	})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	result, err := gen.Scan(context.Background(), testRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Credential files are reported as files, not for their content
+	if len(result.Findings) != 1 || result.Findings[0].Path != "config.py" {
+		t.Errorf("findings = %+v, want one in config.py", result.Findings)
+	}
+	if want := []string{".env", "certs/tls.pem"}; !reflect.DeepEqual(result.CredentialFiles, want) {
+		t.Errorf("credential files = %q, want %q", result.CredentialFiles, want)
+	}
+
+	result, err = gen.Scan(context.Background(), testRoot, []string{"README.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Findings) != 0 || len(result.CredentialFiles) != 2 {
+		t.Errorf("scanning README.md only: %+v", result)
+	}
+}
+
+// TestGeneratedFilesHaveNoSecrets keeps cc's own templates from tripping the
+// scanner it runs on them.
+func TestGeneratedFilesHaveNoSecrets(t *testing.T) {
+	for _, tc := range goldenCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := newTestGenerator(AferoFS{Fs: newMemFS(t, tc.files)})
+
+			config := tc.config
+			config.Root = testRoot
+			plan, err := gen.Plan(context.Background(), &config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Findings) != 0 {
+				t.Errorf("findings = %+v", plan.Findings)
+			}
+		})
+	}
+}
+
+func TestScanPlanChecksClaudeReferences(t *testing.T) {
+	mem := newMemFS(t, map[string]string{
+		"CLAUDE.md":            "See `config/settings.yaml`, [the keys](./deploy/id_rsa) and https://example.com.\n",
+		"config/settings.yaml": "api_key: " + fakePassword + "\n",
+		"deploy/id_rsa":        "-----BEGIN RSA " + "PRIVATE KEY-----\n",
+	})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	// CLAUDE.md exists, so it is kept and its references are scanned
+	plan, err := gen.Plan(context.Background(), &ProjectConfig{Root: testRoot, Name: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, finding := range plan.Findings {
+		got = append(got, finding.Path+"="+finding.Rule)
+	}
+	want := []string{"config/settings.yaml=generic-secret", "deploy/id_rsa=credential-file"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+}
+
+func TestPlanDenySecrets(t *testing.T) {
+	mem := newMemFS(t, nil)
+	gen := newTestGenerator(AferoFS{Fs: mem})
+	if err := gen.InitializeProject(&ProjectConfig{Root: testRoot, Name: "example", Type: "terraform"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(mem, filepath.Join(testRoot, "secrets", "prod.env.local"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(mem, filepath.Join(testRoot, ".env"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := gen.PlanDenySecrets(context.Background(), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := afero.ReadFile(mem, filepath.Join(testRoot, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The type's own rules stay, and credential files are added
+	for _, rule := range []string{`"Bash(terraform apply:*)"`, `"Read(./.env)"`, `"Read(./.env.*.local)"`} {
+		if !strings.Contains(string(settings), rule) {
+			t.Errorf("settings.json has no %s:\n%s", rule, settings)
+		}
+	}
+	if strings.Contains(string(settings), "prod.env.local") {
+		t.Errorf("prod.env.local is not a credential file name:\n%s", settings)
+	}
+
+	manifest, err := gen.LoadManifest(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest.Project.DenySecrets {
+		t.Error("manifest does not record deny_secrets")
+	}
+}
+
+func TestDenySecretsMergesExistingSettings(t *testing.T) {
+	existing := `{
+  "model": "opus",
+  "permissions": {
+    "allow": ["Bash(ls:*)"],
+    "deny": ["Read(./secrets/**)"]
+  }
+}
+`
+	mem := newMemFS(t, map[string]string{".claude/settings.json": existing, ".env": "x"})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+	path := filepath.Join(testRoot, ".claude", "settings.json")
+
+	plan, err := gen.Plan(context.Background(), &ProjectConfig{Root: testRoot, Name: "example", DenySecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range plan.Files {
+		if file.Path == ".claude/settings.json" && file.Action != ActionMerged {
+			t.Errorf("settings.json action = %s, want %s", file.Action, ActionMerged)
+		}
+	}
+	if err := gen.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := afero.ReadFile(mem, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Model       string
+		Permissions struct{ Allow, Deny []string }
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Model != "opus" || !reflect.DeepEqual(settings.Permissions.Allow, []string{"Bash(ls:*)"}) {
+		t.Errorf("user settings not kept:\n%s", data)
+	}
+	wantDeny := []string{"Read(./secrets/**)", "Read(./.env)", "Read(./.env.local)", "Read(./.env.*.local)"}
+	if !reflect.DeepEqual(settings.Permissions.Deny, wantDeny) {
+		t.Errorf("deny = %q, want %q", settings.Permissions.Deny, wantDeny)
+	}
+
+	// The file stays the user's, so uninstall and refresh leave it alone
+	manifest, err := gen.LoadManifest(testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manifest.Files[".claude/settings.json"]; ok {
+		t.Error("merged settings.json is recorded in the manifest")
+	}
+
+	plan, err = gen.PlanDenySecrets(context.Background(), testRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range plan.Files {
+		if file.Path == ".claude/settings.json" && file.Action != ActionUnchanged {
+			t.Errorf("second run settings.json action = %s, want %s", file.Action, ActionUnchanged)
+		}
+	}
+}
+
+func TestDenySecretsSkipsInvalidSettings(t *testing.T) {
+	mem := newMemFS(t, map[string]string{".claude/settings.json": "{ not json", ".env": "x"})
+	gen := newTestGenerator(AferoFS{Fs: mem})
+
+	plan, err := gen.Plan(context.Background(), &ProjectConfig{Root: testRoot, Name: "example", DenySecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range plan.Files {
+		if file.Path == ".claude/settings.json" && file.Action != ActionSkipped {
+			t.Errorf("settings.json action = %s, want %s", file.Action, ActionSkipped)
+		}
+	}
+}
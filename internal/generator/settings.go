@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// Permissions are Claude Code permission rules, such as "Bash(make test)" or
+// "Bash(terraform apply:*)". Deny rules win over allow rules.
+type Permissions struct {
+	Allow []string
+	Deny  []string
+}
+
+type claudeSettings struct {
+	Permissions claudePermissions `json:"permissions"`
+}
+
+type claudePermissions struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// generateClaudeSettings writes the project type's permission rules to
+// .claude/settings.json, the settings file shared through git.
+func (g *Generator) generateClaudeSettings(projectPath string, config *ProjectConfig) error {
+	permissions := g.ptype.Permissions
+	if config.DenySecrets {
+		permissions.Deny = appendUnique(permissions.Deny, secretDenyRules(g.plan.CredentialFiles)...)
+	}
+	if len(permissions.Allow) == 0 && len(permissions.Deny) == 0 {
+		return nil
+	}
+
+	settings := claudeSettings{Permissions: claudePermissions{
+		Allow: permissions.Allow,
+		Deny:  permissions.Deny,
+	}}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to render settings: %w", err)
+	}
+	content := string(data) + "\n"
+
+	path := filepath.Join(projectPath, ".claude", "settings.json")
+	disk, exists, err := g.readDisk(path)
+	if err != nil {
+		return err
+	}
+	if !exists || disk == content || g.config.Overwrite || (g.config.Refresh && g.refreshable(path, disk)) {
+		return g.writeFile(path, content)
+	}
+
+	// The rules matter most in projects that already have settings, so they
+	// are merged into the user's file rather than skipped
+	merged, err := mergeClaudeSettings(disk, permissions)
+	if err != nil {
+		if g.config.Verbose {
+			fmt.Fprintf(g.Log, "Skipping %s: %v\n", path, err)
+		}
+		return g.skipFile(path)
+	}
+	return g.mergeFile(path, merged)
+}
+
+// mergeClaudeSettings adds permission rules to an existing settings file,
+// keeping everything else in it. The content is returned as is if it already
+// has every rule.
+func mergeClaudeSettings(content string, permissions Permissions) (string, error) {
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &settings); err != nil {
+		return "", fmt.Errorf("invalid settings: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]json.RawMessage)
+	}
+
+	var rules map[string]json.RawMessage
+	if raw, ok := settings["permissions"]; ok {
+		if err := json.Unmarshal(raw, &rules); err != nil {
+			return "", fmt.Errorf("invalid permissions: %w", err)
+		}
+	}
+	if rules == nil {
+		rules = make(map[string]json.RawMessage)
+	}
+
+	added := false
+	for _, list := range []struct {
+		key   string
+		rules []string
+	}{{"allow", permissions.Allow}, {"deny", permissions.Deny}} {
+		if len(list.rules) == 0 {
+			continue
+		}
+		var existing []string
+		if raw, ok := rules[list.key]; ok {
+			if err := json.Unmarshal(raw, &existing); err != nil {
+				return "", fmt.Errorf("invalid permissions.%s: %w", list.key, err)
+			}
+		}
+		merged := appendUnique(existing, list.rules...)
+		if len(merged) == len(existing) {
+			continue
+		}
+		raw, err := json.Marshal(merged)
+		if err != nil {
+			return "", err
+		}
+		rules[list.key] = raw
+		added = true
+	}
+	if !added {
+		return content, nil
+	}
+
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	settings["permissions"] = raw
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,284 @@
+package generator
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// daggerVersion is the Dagger engine the module and CI are pinned to.
+const daggerVersion = "0.18.10"
+
+func init() {
+	mustRegisterProjectType(daggerType())
+	mustRegisterAddOn(AddOn{
+		Name:        "dagger",
+		Description: "Run CI through a Dagger pipeline in Go instead of make",
+		Conflicts:   []string{"dagger"},
+		Layer:       daggerAddOn(),
+	})
+}
+
+// daggerType is a project whose CI is a Dagger pipeline. The same pipeline is
+// layered onto other types by the dagger add-on.
+func daggerType() ProjectType {
+	t := daggerAddOn()
+	t.Name = "dagger"
+	t.Description = "Dagger pipeline written in Go, run the same way locally and in CI"
+	t.IssueFields = []IssueField{
+		{ID: "dagger-version", Label: "Dagger version", Description: "Output of `dagger version`", Placeholder: "dagger v" + daggerVersion, Required: true},
+	}
+	t.MakeTargets = append([]MakeTarget{
+		{Name: "install", Help: "Generate the Dagger module's SDK code", Recipe: []string{"@$(MAKE) dagger-develop"}},
+		{Name: "dev", Help: "List the pipeline's functions", Recipe: []string{"dagger functions"}},
+		{Name: "test", Help: "Run the pipeline's tests", Recipe: []string{"@$(MAKE) dagger-test"}},
+		{Name: "lint", Help: "Run the pipeline's linters", Recipe: []string{"@$(MAKE) dagger-lint"}},
+		{Name: "build", Help: "Run the pipeline's build", Recipe: []string{"@$(MAKE) dagger-build"}},
+	}, t.MakeTargets...)
+	return t
+}
+
+// daggerAddOn holds everything the Dagger pipeline adds to a project,
+// whatever its type.
+func daggerAddOn() ProjectType {
+	return ProjectType{
+		Stacks: []string{StackDagger},
+		MakeTargets: []MakeTarget{
+			{Name: "dagger-develop", Help: "Generate the Dagger module's SDK code", Recipe: []string{"dagger develop"}},
+			{Name: "dagger-test", Help: "Run tests in the Dagger pipeline", Recipe: []string{"dagger call test"}},
+			{Name: "dagger-lint", Help: "Run linters in the Dagger pipeline", Recipe: []string{"dagger call lint"}},
+			{Name: "dagger-build", Help: "Run the build in the Dagger pipeline", Recipe: []string{"dagger call build"}},
+			{Name: "ci", Help: "Run the full CI pipeline locally", Recipe: []string{
+				"dagger call test",
+				"dagger call lint",
+				"dagger call build",
+			}},
+		},
+		Permissions: Permissions{
+			Allow: []string{
+				"Bash(dagger call:*)",
+				"Bash(dagger functions)",
+				"Bash(make ci)",
+			},
+		},
+		Claude: `## Dagger Pipeline
+
+CI runs in containers defined in Go with [Dagger](https://docs.dagger.io), so it
+behaves the same on a laptop as in GitHub Actions.
+
+- ` + "`dagger.json`" + ` - Module definition; the pipeline source is in ` + "`.dagger/`" + `
+- ` + "`.dagger/main.go`" + ` - The ` + "`Test`" + `, ` + "`Lint`" + ` and ` + "`Build`" + ` functions CI calls
+
+Running it locally needs the ` + "`dagger`" + ` CLI (v` + daggerVersion + `) and a container runtime:
+
+` + "```bash" + `
+make dagger-develop            # Generate the SDK code once, and after upgrades
+dagger functions               # List the pipeline's functions
+make ci                        # Test, lint and build, exactly as CI does
+dagger call test               # Run a single function
+dagger call build terminal     # Open a shell in the build container
+` + "```" + `
+
+When CI fails, reproduce it with ` + "`dagger call`" + ` before changing anything. After
+editing ` + "`.dagger/main.go`" + `, run ` + "`dagger functions`" + ` to check that the module
+still loads, and keep every step of CI inside the pipeline rather than in the
+workflow file.
+`,
+		Generators: []FileGenerator{generateDaggerFiles},
+	}
+}
+
+// daggerPipeline is what the generated pipeline runs for a project type.
+// Commands are shell snippets run in Image with the source in /src.
+type daggerPipeline struct {
+	Image  string
+	Caches [][2]string // cache volume name and mount path
+	Setup  string
+	Test   string
+	Lint   string
+	// Build is run in the image, unless the project has a Containerfile,
+	// which the Build function builds instead
+	Build         string
+	Containerfile bool
+}
+
+func daggerPipelineFor(projectType string) daggerPipeline {
+	switch projectType {
+	case "go":
+		return daggerPipeline{
+			Image: "golang:1.24",
+			Caches: [][2]string{
+				{"go-mod", "/go/pkg/mod"},
+				{"go-build", "/root/.cache/go-build"},
+			},
+			Setup:         "go mod download",
+			Test:          "go test -race ./...",
+			Lint:          "go vet ./... && go run github.com/golangci/golangci-lint/v2/cmd/golangci-lint@" + golangciLintVersion + " run ./...",
+			Containerfile: true,
+		}
+	case "python-fastapi":
+		return daggerPipeline{
+			Image:         "ghcr.io/astral-sh/uv:" + uvVersion + "-python3.12-bookworm-slim",
+			Caches:        [][2]string{{"uv", "/root/.cache/uv"}},
+			Setup:         "uv sync --locked",
+			Test:          "uv run pytest",
+			Lint:          "uv run ruff check . && uv run ruff format --check .",
+			Containerfile: true,
+		}
+	case "airflow":
+		return daggerPipeline{
+			Image:  "ghcr.io/astral-sh/uv:" + uvVersion + "-python3.12-bookworm-slim",
+			Caches: [][2]string{{"uv", "/root/.cache/uv"}},
+			Setup:  "uv sync --locked",
+			Test:   "uv run pytest",
+			Lint:   "uv run ruff check . && uv run ruff format --check .",
+			Build:  "uv run pytest tests/test_dag_integrity.py",
+		}
+	case "terraform":
+		validate := `for dir in environments/*/; do (cd "$dir" && terraform init -backend=false -input=false >/dev/null && terraform validate) || exit 1; done`
+		return daggerPipeline{
+			Image: "hashicorp/terraform:1.9",
+			Test:  validate,
+			Lint:  "terraform fmt -check -recursive",
+			Build: validate,
+		}
+	}
+
+	return daggerPipeline{
+		Image: "alpine:3.22",
+		Test:  `echo "Add your test commands to .dagger/main.go"`,
+		Lint:  `echo "Add your linting commands to .dagger/main.go"`,
+		Build: `echo "Add your build commands to .dagger/main.go"`,
+	}
+}
+
+// daggerObjectName is the Go type Dagger expects for a module name:
+// "billing-api" becomes BillingApi.
+func daggerObjectName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 || !unicode.IsLetter([]rune(b.String())[0]) {
+		return "Pipeline" + b.String()
+	}
+	return b.String()
+}
+
+func generateDaggerFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	module := strings.ToLower(config.Name)
+	object := daggerObjectName(config.Name)
+	pipeline := daggerPipelineFor(config.Type)
+
+	daggerJSON := `{
+  "name": ` + strconv.Quote(module) + `,
+  "engineVersion": "v` + daggerVersion + `",
+  "sdk": {
+    "source": "go"
+  },
+  "source": ".dagger"
+}
+`
+
+	goMod := `module dagger/` + module + `
+
+go 1.24
+`
+
+	// Generated by dagger develop
+	gitignore := `/dagger.gen.go
+/internal/dagger
+/internal/querybuilder
+/internal/telemetry
+`
+
+	env := "\treturn dag.Container().\n\t\tFrom(" + strconv.Quote(pipeline.Image) + ").\n"
+	for _, cache := range pipeline.Caches {
+		env += "\t\tWithMountedCache(" + strconv.Quote(cache[1]) + ", dag.CacheVolume(" + strconv.Quote(cache[0]) + ")).\n"
+	}
+	env += "\t\tWithDirectory(\"/src\", source).\n\t\tWithWorkdir(\"/src\")"
+	if pipeline.Setup != "" {
+		env += ".\n\t\tWithExec(sh(" + strconv.Quote(pipeline.Setup) + "))"
+	}
+	env += "\n"
+
+	build := `// Build builds the project and returns the container it was built in
+func (m *` + object + `) Build(
+	// +defaultPath="/"
+	source *dagger.Directory,
+) *dagger.Container {
+	return m.Env(source).WithExec(sh(` + strconv.Quote(pipeline.Build) + `))
+}
+`
+	if pipeline.Containerfile {
+		build = `// Build builds the image from the Containerfile. Publish it with:
+// dagger call build publish --address=registry.example.com/` + module + `:latest
+func (m *` + object + `) Build(
+	// +defaultPath="/"
+	source *dagger.Directory,
+) *dagger.Container {
+	return source.DockerBuild(dagger.DirectoryDockerBuildOpts{Dockerfile: "Containerfile"})
+}
+`
+	}
+
+	main := `// The CI pipeline for ` + config.Name + `. CI runs these functions with dagger call,
+// and so can you: dagger call test
+package main
+
+import (
+	"context"
+
+	"dagger/` + module + `/internal/dagger"
+)
+
+type ` + object + ` struct{}
+
+// Test runs the test suite
+func (m *` + object + `) Test(
+	ctx context.Context,
+	// +defaultPath="/"
+	source *dagger.Directory,
+) (string, error) {
+	return m.Env(source).WithExec(sh(` + strconv.Quote(pipeline.Test) + `)).Stdout(ctx)
+}
+
+// Lint runs the linters
+func (m *` + object + `) Lint(
+	ctx context.Context,
+	// +defaultPath="/"
+	source *dagger.Directory,
+) (string, error) {
+	return m.Env(source).WithExec(sh(` + strconv.Quote(pipeline.Lint) + `)).Stdout(ctx)
+}
+
+` + build + `
+// Env returns a container with the project's toolchain and source in /src
+func (m *` + object + `) Env(
+	// +defaultPath="/"
+	source *dagger.Directory,
+) *dagger.Container {
+` + env + `}
+
+func sh(script string) []string {
+	return []string{"sh", "-c", script}
+}
+`
+
+	return []File{
+		{Path: "dagger.json", Content: daggerJSON},
+		{Path: ".dagger/go.mod", Content: goMod},
+		{Path: ".dagger/.gitignore", Content: gitignore},
+		{Path: ".dagger/main.go", Content: main},
+	}, nil
+}
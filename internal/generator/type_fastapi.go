@@ -0,0 +1,265 @@
+package generator
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	mustRegisterProjectType(fastAPIType())
+}
+
+func fastAPIType() ProjectType {
+	return ProjectType{
+		Name:        "python-fastapi",
+		Description: "Async FastAPI service managed with uv, tested with pytest and linted with ruff",
+		IssueFields: []IssueField{
+			{ID: "python-version", Label: "Python version", Placeholder: "3.12.4", Required: true},
+			{ID: "fastapi-version", Label: "FastAPI version", Description: "Output of `uv pip show fastapi`", Placeholder: "0.115.6"},
+		},
+		Stacks: []string{StackPython, StackUV},
+		MakeTargets: []MakeTarget{
+			{Name: "install", Help: "Install dependencies with uv", Recipe: []string{"uv sync"}},
+			{Name: "dev", Help: "Run the API with auto-reload", Recipe: []string{"uv run uvicorn app.main:app --reload"}},
+			{Name: "test", Help: "Run tests", Recipe: []string{"uv run pytest"}},
+			{Name: "lint", Help: "Check linting and formatting with ruff", Recipe: []string{
+				"uv run ruff check .",
+				"uv run ruff format --check .",
+			}},
+			{Name: "format", Help: "Fix linting and formatting with ruff", Recipe: []string{
+				"uv run ruff check --fix .",
+				"uv run ruff format .",
+			}},
+			{Name: "build", Help: "Build the container image with Podman", Recipe: []string{
+				"uv lock",
+				"podman build -t $(notdir $(CURDIR)) -f Containerfile .",
+			}},
+			{Name: "run", Help: "Run the container image on port 8000", Recipe: []string{
+				"podman run --rm -p 8000:8000 $(notdir $(CURDIR))",
+			}},
+		},
+		GitIgnore: []string{".venv/", ".ruff_cache/"},
+		Claude: `## FastAPI Project
+
+Python ` + "`>=3.12`" + `, managed with [uv](https://docs.astral.sh/uv/). Always use
+` + "`uv run`" + ` or the make targets rather than a global Python.
+
+- ` + "`app/main.py`" + ` - Application factory (` + "`create_app`" + `) and lifespan hooks
+- ` + "`app/config.py`" + ` - Settings read from ` + "`APP_*`" + ` environment variables or ` + "`.env`" + `
+- ` + "`app/routers/`" + ` - One ` + "`APIRouter`" + ` per module, included in ` + "`create_app`" + `
+- ` + "`tests/`" + ` - Async pytest tests using an httpx client bound to the app
+- ` + "`Containerfile`" + ` - Production image, built with ` + "`make build`" + ` (Podman)
+
+Conventions:
+
+- Endpoints are ` + "`async def`" + `; never block the event loop with synchronous I/O
+- Inject settings and other dependencies with ` + "`Annotated[..., Depends(...)]`" + `
+- Declare request and response bodies as Pydantic models
+- Add a test in ` + "`tests/`" + ` for every new route
+- Add dependencies with ` + "`uv add <package>`" + ` (` + "`uv add --dev`" + ` for tools) and commit ` + "`uv.lock`" + `
+- Once dependencies are cached, ` + "`UV_OFFLINE=1 make test`" + ` runs without network access
+`,
+		Generators: []FileGenerator{generateFastAPIFiles},
+	}
+}
+
+var pythonNameRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// pythonProjectName normalizes name as PEP 503 does for package names.
+func pythonProjectName(name string) string {
+	normalized := strings.Trim(pythonNameRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if normalized == "" {
+		return "app"
+	}
+	return normalized
+}
+
+func generateFastAPIFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	name := pythonProjectName(config.Name)
+
+	pyproject := `[project]
+name = "` + name + `"
+version = "0.1.0"
+description = "` + strings.ReplaceAll(config.Description, `"`, `\"`) + `"
+requires-python = ">=3.12"
+dependencies = [
+    "fastapi>=0.115",
+    "pydantic-settings>=2.6",
+    "uvicorn[standard]>=0.32",
+]
+
+[dependency-groups]
+dev = [
+    "httpx>=0.28",
+    "pytest>=8.3",
+    "pytest-asyncio>=0.25",
+    "ruff>=0.8",
+]
+
+[tool.uv]
+package = false
+
+[tool.pytest.ini_options]
+testpaths = ["tests"]
+pythonpath = ["."]
+asyncio_mode = "auto"
+asyncio_default_fixture_loop_scope = "function"
+
+[tool.ruff]
+line-length = 100
+target-version = "py312"
+
+[tool.ruff.lint]
+select = ["E", "F", "I", "B", "UP", "ASYNC", "SIM"]
+`
+
+	settings := `from functools import lru_cache
+
+from pydantic_settings import BaseSettings, SettingsConfigDict
+
+
+class Settings(BaseSettings):
+    """Application settings, read from APP_* environment variables or .env."""
+
+    model_config = SettingsConfigDict(env_prefix="APP_", env_file=".env", extra="ignore")
+
+    name: str = "` + name + `"
+    debug: bool = False
+    log_level: str = "info"
+
+
+@lru_cache
+def get_settings() -> Settings:
+    return Settings()
+`
+
+	main := `import logging
+from collections.abc import AsyncIterator
+from contextlib import asynccontextmanager
+
+from fastapi import FastAPI
+
+from app.config import get_settings
+from app.routers import health
+
+logger = logging.getLogger(__name__)
+
+
+@asynccontextmanager
+async def lifespan(app: FastAPI) -> AsyncIterator[None]:
+    settings = get_settings()
+    logging.basicConfig(level=settings.log_level.upper())
+    logger.info("starting %s", settings.name)
+    yield
+    logger.info("stopping %s", settings.name)
+
+
+def create_app() -> FastAPI:
+    settings = get_settings()
+    app = FastAPI(title=settings.name, debug=settings.debug, lifespan=lifespan)
+    app.include_router(health.router)
+    return app
+
+
+app = create_app()
+`
+
+	health := `from typing import Annotated
+
+from fastapi import APIRouter, Depends
+from pydantic import BaseModel
+
+from app.config import Settings, get_settings
+
+router = APIRouter(tags=["health"])
+
+
+class Health(BaseModel):
+    status: str
+    name: str
+
+
+@router.get("/health")
+async def get_health(settings: Annotated[Settings, Depends(get_settings)]) -> Health:
+    return Health(status="ok", name=settings.name)
+`
+
+	conftest := `from collections.abc import AsyncIterator
+
+import pytest
+from httpx import ASGITransport, AsyncClient
+
+from app.main import create_app
+
+
+@pytest.fixture
+async def client() -> AsyncIterator[AsyncClient]:
+    transport = ASGITransport(app=create_app())
+    async with AsyncClient(transport=transport, base_url="http://test") as client:
+        yield client
+`
+
+	testHealth := `from httpx import AsyncClient
+
+
+async def test_health(client: AsyncClient) -> None:
+    response = await client.get("/health")
+
+    assert response.status_code == 200
+    assert response.json() == {"status": "ok", "name": "` + name + `"}
+
+
+async def test_unknown_route(client: AsyncClient) -> None:
+    response = await client.get("/missing")
+
+    assert response.status_code == 404
+`
+
+	containerfile := `# Build with: podman build -t ` + name + ` -f Containerfile .
+FROM docker.io/library/python:3.12-slim AS build
+
+COPY --from=ghcr.io/astral-sh/uv:` + uvVersion + ` /uv /bin/uv
+ENV UV_COMPILE_BYTECODE=1 UV_LINK_MODE=copy
+
+WORKDIR /app
+COPY pyproject.toml uv.lock ./
+RUN uv sync --locked --no-dev
+COPY app ./app
+
+FROM docker.io/library/python:3.12-slim
+
+RUN useradd --create-home --uid 1000 app
+WORKDIR /app
+COPY --from=build /app /app
+ENV PATH="/app/.venv/bin:$PATH"
+
+USER app
+EXPOSE 8000
+CMD ["uvicorn", "app.main:app", "--host", "0.0.0.0", "--port", "8000"]
+`
+
+	containerignore := `.git/
+.venv/
+.env
+__pycache__/
+.pytest_cache/
+.ruff_cache/
+tests/
+`
+
+	return []File{
+		{Path: "pyproject.toml", Content: pyproject},
+		{Path: ".python-version", Content: "3.12\n"},
+		{Path: "app/__init__.py", Content: ""},
+		{Path: "app/config.py", Content: settings},
+		{Path: "app/main.py", Content: main},
+		{Path: "app/routers/__init__.py", Content: ""},
+		{Path: "app/routers/health.py", Content: health},
+		{Path: "tests/__init__.py", Content: ""},
+		{Path: "tests/conftest.py", Content: conftest},
+		{Path: "tests/test_health.py", Content: testHealth},
+		{Path: "Containerfile", Content: containerfile},
+		{Path: ".containerignore", Content: containerignore},
+	}, nil
+}
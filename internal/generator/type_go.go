@@ -0,0 +1,308 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+)
+
+// golangciLintVersion is run with go run, so make lint works the same locally
+// and in CI without installing anything.
+const golangciLintVersion = "v2.1.6"
+
+func init() {
+	mustRegisterProjectType(goType())
+}
+
+func goType() ProjectType {
+	return ProjectType{
+		Name:        "go",
+		Description: "Go service using the standard library, log/slog and a distroless container",
+		IssueFields: []IssueField{
+			{ID: "go-version", Label: "Go version", Description: "Output of `go version`", Placeholder: "go version go1.24.4 linux/amd64", Required: true},
+		},
+		Stacks: []string{StackGo},
+		MakeTargets: []MakeTarget{
+			{Name: "install", Help: "Download module dependencies", Recipe: []string{"go mod download"}},
+			{Name: "dev", Help: "Run the service", Recipe: []string{"go run ./cmd/..."}},
+			{Name: "test", Help: "Run tests with the race detector", Recipe: []string{"go test -race ./..."}},
+			{Name: "cover", Help: "Run tests and report coverage", Recipe: []string{
+				"go test -race -coverprofile=coverage.out ./...",
+				"go tool cover -func=coverage.out",
+			}},
+			{Name: "vet", Help: "Run go vet", Recipe: []string{"go vet ./..."}},
+			{Name: "lint", Help: "Run golangci-lint", Recipe: []string{
+				"go run github.com/golangci/golangci-lint/v2/cmd/golangci-lint@" + golangciLintVersion + " run ./...",
+			}},
+			{Name: "build", Help: "Build binaries into bin/", Recipe: []string{"go build -trimpath -o bin/ ./cmd/..."}},
+			{Name: "clean", Help: "Clean build artifacts", Recipe: []string{"rm -rf bin/ dist/ coverage.out"}},
+			{Name: "image", Help: "Build the container image with Podman", Recipe: []string{
+				"podman build -t $(notdir $(CURDIR)) -f Containerfile .",
+			}},
+		},
+		GitIgnore: []string{"bin/", "coverage.out"},
+		Claude: `## Go Project
+
+- ` + "`cmd/<name>/main.go`" + ` - Entry point: configuration, logging and graceful shutdown only
+- ` + "`internal/`" + ` - Application packages, not importable by other modules
+- ` + "`.golangci.yml`" + ` - Linter configuration, run with ` + "`make lint`" + `
+- ` + "`Containerfile`" + ` - Static binary on a distroless image, built with ` + "`make image`" + `
+
+Conventions:
+
+- Standard library first; add a dependency only when it saves real work
+- Pass ` + "`context.Context`" + ` as the first parameter of anything that blocks or does I/O
+- Wrap errors with context: ` + "`fmt.Errorf(\"failed to load config: %w\", err)`" + `
+- Log with ` + "`log/slog`" + ` key-value pairs (` + "`logger.Info(\"started\", \"addr\", addr)`" + `), never ` + "`fmt.Println`" + `
+- Inject the logger and other dependencies; avoid package-level state
+- Write table-driven tests next to the code (` + "`foo_test.go`" + `); ` + "`make test`" + ` runs them with ` + "`-race`" + `
+- Run ` + "`make vet lint test`" + ` before committing
+`,
+		Generators: []FileGenerator{generateGoFiles},
+	}
+}
+
+var goModuleRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._~-]*(/[A-Za-z0-9._~-]+)*$`)
+
+func generateGoFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	module := config.Module
+	if !goModuleRe.MatchString(module) {
+		return nil, fmt.Errorf("invalid Go module path %q", module)
+	}
+	name := config.Name
+
+	goMod := `module ` + module + `
+
+go 1.24
+`
+
+	main := `package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"` + module + `/internal/server"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel()}))
+	slog.SetDefault(logger)
+
+	if err := run(logger); err != nil {
+		logger.Error("exiting", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	addr := ":8080"
+	if port := os.Getenv("PORT"); port != "" {
+		addr = ":" + port
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           server.New(logger, version),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.Info("listening", "addr", addr, "version", version)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	// Give in-flight requests time to finish
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
+
+// logLevel reads the level from LOG_LEVEL (debug, info, warn or error).
+func logLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(os.Getenv("LOG_LEVEL"))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+`
+
+	server := `// Package server implements the HTTP API.
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// New returns the service's HTTP handler.
+func New(logger *slog.Logger, version string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version})
+	})
+
+	return logRequests(logger, mux)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func logRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.LogAttrs(r.Context(), slog.LevelInfo, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+`
+
+	serverTest := `package server
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	handler := New(slog.New(slog.DiscardHandler), "test")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "health", method: http.MethodGet, path: "/healthz", wantStatus: http.StatusOK, wantBody: ` + "`" + `"status":"ok"` + "`" + `},
+		{name: "health reports version", method: http.MethodGet, path: "/healthz", wantStatus: http.StatusOK, wantBody: ` + "`" + `"version":"test"` + "`" + `},
+		{name: "wrong method", method: http.MethodPost, path: "/healthz", wantStatus: http.StatusMethodNotAllowed},
+		{name: "unknown path", method: http.MethodGet, path: "/missing", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+`
+
+	golangci := `# golangci-lint configuration: https://golangci-lint.run/usage/configuration/
+version: "2"
+
+linters:
+  default: standard
+  enable:
+    - bodyclose
+    - errorlint
+    - gocritic
+    - misspell
+    - revive
+    - sloglint
+    - unconvert
+  settings:
+    sloglint:
+      kv-only: true
+  exclusions:
+    rules:
+      # Best-effort writes of an HTTP response body
+      - linters: [errcheck]
+        source: "json.NewEncoder\\(w\\).Encode"
+
+formatters:
+  enable:
+    - gofmt
+    - goimports
+`
+
+	containerfile := `# Build with: podman build -t ` + name + ` -f Containerfile .
+FROM docker.io/library/golang:1.24 AS build
+
+WORKDIR /src
+COPY go.mod go.sum* ./
+RUN go mod download
+COPY . .
+RUN CGO_ENABLED=0 go build -trimpath -ldflags="-s -w" -o /out/` + name + ` ./cmd/` + name + `
+
+FROM gcr.io/distroless/static-debian12:nonroot
+
+COPY --from=build /out/` + name + ` /` + name + `
+USER nonroot:nonroot
+EXPOSE 8080
+ENTRYPOINT ["/` + name + `"]
+`
+
+	containerignore := `.git/
+bin/
+dist/
+coverage.out
+`
+
+	return []File{
+		{Path: "go.mod", Content: goMod},
+		{Path: "cmd/" + name + "/main.go", Content: main},
+		{Path: "internal/server/server.go", Content: server},
+		{Path: "internal/server/server_test.go", Content: serverTest},
+		{Path: ".golangci.yml", Content: golangci},
+		{Path: "Containerfile", Content: containerfile},
+		{Path: ".containerignore", Content: containerignore},
+	}, nil
+}
@@ -0,0 +1,479 @@
+package generator
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// kubeconformVersion is the release CI downloads to validate manifests.
+const kubeconformVersion = "v0.6.7"
+
+// kubernetesImage is the example workload: nginx running as a non-root user
+// on port 8080.
+const kubernetesImage = "docker.io/nginxinc/nginx-unprivileged"
+
+func init() {
+	mustRegisterProjectType(kubernetesType())
+	mustRegisterAddOn(helmAddOn())
+}
+
+func kubernetesType() ProjectType {
+	refuseRemote := `@case "$(KUBE_CONTEXT)" in minikube|kind-*|docker-desktop) ;; *) echo "Refusing to use non-local context $(KUBE_CONTEXT)"; exit 1;; esac`
+
+	return ProjectType{
+		Name:        "kubernetes",
+		Description: "Kustomize base and overlays for minikube, validated with kubeconform; --with helm adds a chart",
+		IssueFields: []IssueField{
+			{ID: "kubernetes-version", Label: "Kubernetes version", Description: "Output of `kubectl version`", Placeholder: "v1.31.0", Required: true},
+			{ID: "overlay", Label: "Overlay", Options: []string{"dev", "prod"}, Required: true},
+		},
+		Stacks: []string{StackKubernetes},
+		MakeVars: []string{
+			"KUBE_CONTEXT ?= minikube",
+			"OVERLAY ?= dev",
+			"KUSTOMIZE ?= kubectl kustomize",
+			"KUBECONFORM ?= kubeconform -strict -summary -schema-location default -schema-location 'https://raw.githubusercontent.com/datreeio/CRDs-catalog/main/{{.Group}}/{{.ResourceKind}}_{{.ResourceAPIVersion}}.json'",
+		},
+		MakeTargets: []MakeTarget{
+			{Name: "install", Help: "Check that the required tools are installed", Recipe: []string{
+				`@for tool in kubectl minikube kubeconform; do command -v $$tool >/dev/null || echo "Missing $$tool"; done`,
+			}},
+			{Name: "dev", Help: "Start minikube and deploy the dev overlay", Recipe: []string{"@$(MAKE) cluster-up deploy"}},
+			{Name: "test", Help: "Validate rendered manifests with kubeconform", Recipe: []string{"@$(MAKE) validate"}},
+			{Name: "lint", Help: "Render every overlay and lint Helm charts", Recipe: []string{
+				`@for overlay in k8s/overlays/*/; do $(KUSTOMIZE) $$overlay >/dev/null || exit 1; done`,
+				`@if [ -d charts ]; then for chart in charts/*/; do helm lint --strict $$chart || exit 1; done; fi`,
+			}},
+			{Name: "build", Help: "Render every overlay into dist/", Recipe: []string{
+				`@mkdir -p dist`,
+				`@for overlay in k8s/overlays/*/; do $(KUSTOMIZE) $$overlay > dist/$$(basename $$overlay).yaml || exit 1; done`,
+			}},
+			{Name: "validate", Help: "Validate overlays and charts against Kubernetes schemas", Recipe: []string{
+				`@for overlay in k8s/overlays/*/; do echo "Validating $$overlay"; $(KUSTOMIZE) $$overlay | $(KUBECONFORM) || exit 1; done`,
+				`@if [ -d charts ]; then for chart in charts/*/; do echo "Validating $$chart"; helm template $$chart | $(KUBECONFORM) || exit 1; done; fi`,
+			}},
+			{Name: "render", Help: "Print the manifests of OVERLAY (default dev)", Recipe: []string{"$(KUSTOMIZE) k8s/overlays/$(OVERLAY)"}},
+			{Name: "cluster-up", Help: "Start the local minikube cluster with ingress", Recipe: []string{
+				refuseRemote,
+				"minikube start --profile $(KUBE_CONTEXT) --addons=ingress",
+			}},
+			{Name: "cluster-down", Help: "Stop the local minikube cluster", Recipe: []string{
+				refuseRemote,
+				"minikube stop --profile $(KUBE_CONTEXT)",
+			}},
+			{Name: "deploy", Help: "Apply OVERLAY to the local cluster", Recipe: []string{
+				refuseRemote,
+				"$(KUSTOMIZE) k8s/overlays/$(OVERLAY) | kubectl --context $(KUBE_CONTEXT) apply -f -",
+			}},
+			{Name: "undeploy", Help: "Delete OVERLAY from the local cluster", Recipe: []string{
+				refuseRemote,
+				"$(KUSTOMIZE) k8s/overlays/$(OVERLAY) | kubectl --context $(KUBE_CONTEXT) delete --ignore-not-found -f -",
+			}},
+		},
+		GitIgnore: []string{"dist/", "charts/*/charts/", "*.tgz"},
+		PreCommit: `  - repo: local
+    hooks:
+      - id: kubeconform
+        name: validate Kubernetes manifests
+        entry: make validate
+        language: system
+        files: ^(k8s|charts)/
+        pass_filenames: false
+`,
+		Permissions: Permissions{
+			Allow: []string{
+				"Bash(kubectl kustomize:*)",
+				"Bash(kubectl --context minikube:*)",
+				"Bash(make validate)",
+				"Bash(make render:*)",
+				"Bash(helm lint:*)",
+				"Bash(helm template:*)",
+			},
+			Deny: []string{
+				"Bash(kubectl config use-context:*)",
+				"Bash(kubectl config set-context:*)",
+			},
+		},
+		Claude: `## Kubernetes Project
+
+- ` + "`k8s/base/`" + ` - Deployment and Service shared by every environment
+- ` + "`k8s/components/ingress/`" + `, ` + "`k8s/components/monitoring/`" + ` - Optional pieces (Ingress, Prometheus ServiceMonitor) that overlays include
+- ` + "`k8s/overlays/dev/`" + `, ` + "`k8s/overlays/prod/`" + ` - Per-environment namespace, replicas and components
+- ` + "`charts/`" + ` - Helm chart with a values schema, if generated with ` + "`--with helm`" + `
+
+**Never run kubectl against a context other than a local cluster.** Always pass
+` + "`--context minikube`" + ` (or use the make targets, which refuse other contexts), and
+never switch the current context. Changes reach shared clusters only through
+reviewed pull requests.
+
+Conventions:
+
+- Put shared resources in the base and only environment differences in overlays
+- Render with ` + "`make render OVERLAY=prod`" + ` and run ` + "`make validate`" + ` after every change; manifests must build with ` + "`kustomize build`" + ` offline
+- Containers run as non-root with resource requests, limits and probes
+- Use ` + "`make dev`" + ` to start minikube and deploy, ` + "`make undeploy`" + ` to clean up
+`,
+		Generators: []FileGenerator{generateKubernetesFiles},
+	}
+}
+
+var kubernetesNameRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// kubernetesName turns name into a valid resource name (RFC 1123 label).
+func kubernetesName(name string) string {
+	normalized := strings.Trim(kubernetesNameRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(normalized) > 50 {
+		normalized = strings.Trim(normalized[:50], "-")
+	}
+	if normalized == "" {
+		return "app"
+	}
+	return normalized
+}
+
+func generateKubernetesFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	name := kubernetesName(config.Name)
+
+	files := []File{
+		{Path: "k8s/base/kustomization.yaml", Content: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+resources:
+  - deployment.yaml
+  - service.yaml
+`},
+		{Path: "k8s/base/deployment.yaml", Content: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: ` + name + `
+  labels:
+    app.kubernetes.io/name: ` + name + `
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: ` + name + `
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: ` + name + `
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+        - name: ` + name + `
+          image: ` + kubernetesImage + `:1.27-alpine
+          ports:
+            - name: http
+              containerPort: 8080
+          readinessProbe:
+            httpGet:
+              path: /
+              port: http
+          livenessProbe:
+            httpGet:
+              path: /
+              port: http
+          resources:
+            requests:
+              cpu: 50m
+              memory: 64Mi
+            limits:
+              memory: 128Mi
+          securityContext:
+            allowPrivilegeEscalation: false
+            readOnlyRootFilesystem: true
+            capabilities:
+              drop: [ALL]
+          volumeMounts:
+            - name: tmp
+              mountPath: /tmp
+      volumes:
+        - name: tmp
+          emptyDir: {}
+`},
+		{Path: "k8s/base/service.yaml", Content: `apiVersion: v1
+kind: Service
+metadata:
+  name: ` + name + `
+  labels:
+    app.kubernetes.io/name: ` + name + `
+spec:
+  selector:
+    app.kubernetes.io/name: ` + name + `
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+`},
+		{Path: "k8s/components/ingress/kustomization.yaml", Content: `apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+resources:
+  - ingress.yaml
+`},
+		{Path: "k8s/components/ingress/ingress.yaml", Content: `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ` + name + `
+spec:
+  ingressClassName: nginx
+  rules:
+    - host: ` + name + `.local
+      http:
+        paths:
+          - path: /
+            pathType: Prefix
+            backend:
+              service:
+                name: ` + name + `
+                port:
+                  name: http
+`},
+		{Path: "k8s/components/monitoring/kustomization.yaml", Content: `apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+
+# Requires the Prometheus Operator CRDs in the cluster
+resources:
+  - servicemonitor.yaml
+`},
+		{Path: "k8s/components/monitoring/servicemonitor.yaml", Content: `apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: ` + name + `
+spec:
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: ` + name + `
+  endpoints:
+    - port: http
+      path: /metrics
+      interval: 30s
+`},
+		{Path: "k8s/overlays/dev/kustomization.yaml", Content: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+namespace: ` + name + `-dev
+
+resources:
+  - namespace.yaml
+  - ../../base
+
+components:
+  - ../../components/ingress
+`},
+		{Path: "k8s/overlays/dev/namespace.yaml", Content: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + name + `-dev
+`},
+		{Path: "k8s/overlays/prod/kustomization.yaml", Content: `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+namespace: ` + name + `
+
+resources:
+  - namespace.yaml
+  - ../../base
+
+components:
+  - ../../components/ingress
+  - ../../components/monitoring
+
+replicas:
+  - name: ` + name + `
+    count: 3
+
+images:
+  - name: ` + kubernetesImage + `
+    newTag: 1.27.3-alpine
+`},
+		{Path: "k8s/overlays/prod/namespace.yaml", Content: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: ` + name + `
+`},
+	}
+
+	return files, nil
+}
+
+// helmAddOn adds a Helm chart, linted and rendered in CI, to any project.
+func helmAddOn() AddOn {
+	return AddOn{
+		Name:        "helm",
+		Description: "Helm chart with a values schema, linted in CI",
+		Layer: ProjectType{
+			MakeTargets: []MakeTarget{
+				{Name: "helm-lint", Help: "Lint and render every Helm chart", Recipe: []string{
+					`@for chart in charts/*/; do helm lint --strict $$chart && helm template $$chart >/dev/null || exit 1; done`,
+				}},
+			},
+			// helm comes with the runner image
+			CIJobs: ciJob("helm", "Lint Helm charts", "helm-lint"),
+			Permissions: Permissions{
+				Allow: []string{"Bash(helm lint:*)", "Bash(helm template:*)"},
+				Deny:  []string{"Bash(helm install:*)", "Bash(helm upgrade:*)", "Bash(helm uninstall:*)"},
+			},
+			Claude: `## Helm Chart
+
+- ` + "`charts/<name>/`" + ` - The chart; ` + "`values.schema.json`" + ` validates every values file
+
+Change ` + "`values.yaml`" + ` and ` + "`values.schema.json`" + ` together, bump ` + "`version`" + ` in ` + "`Chart.yaml`" + `
+for every chart change, and run ` + "`make helm-lint`" + `. Claude may lint and render
+charts but not install, upgrade or uninstall releases.
+`,
+			Generators: []FileGenerator{generateHelmFiles},
+		},
+	}
+}
+
+func generateHelmFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	return helmChartFiles(kubernetesName(config.Name), config.Description), nil
+}
+
+func helmChartFiles(name, description string) []File {
+	dir := "charts/" + name + "/"
+
+	return []File{
+		{Path: dir + "Chart.yaml", Content: `apiVersion: v2
+name: ` + name + `
+description: ` + strconv.Quote(description) + `
+type: application
+version: 0.1.0
+appVersion: "1.27"
+`},
+		{Path: dir + "values.yaml", Content: `replicaCount: 1
+
+image:
+  repository: ` + kubernetesImage + `
+  tag: "1.27-alpine"
+  pullPolicy: IfNotPresent
+
+service:
+  type: ClusterIP
+  port: 80
+
+resources:
+  requests:
+    cpu: 50m
+    memory: 64Mi
+  limits:
+    memory: 128Mi
+`},
+		{Path: dir + "values.schema.json", Content: `{
+  "$schema": "https://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["replicaCount", "image", "service"],
+  "properties": {
+    "replicaCount": {
+      "type": "integer",
+      "minimum": 0
+    },
+    "image": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["repository", "tag"],
+      "properties": {
+        "repository": {"type": "string", "minLength": 1},
+        "tag": {"type": "string", "minLength": 1},
+        "pullPolicy": {"type": "string", "enum": ["Always", "IfNotPresent", "Never"]}
+      }
+    },
+    "service": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["port"],
+      "properties": {
+        "type": {"type": "string", "enum": ["ClusterIP", "NodePort", "LoadBalancer"]},
+        "port": {"type": "integer", "minimum": 1, "maximum": 65535}
+      }
+    },
+    "resources": {
+      "type": "object"
+    }
+  }
+}
+`},
+		{Path: dir + ".helmignore", Content: `.git/
+*.tgz
+`},
+		{Path: dir + "templates/_helpers.tpl", Content: `{{- define "` + name + `.labels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end }}
+
+{{- define "` + name + `.selectorLabels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end }}
+`},
+		{Path: dir + "templates/deployment.yaml", Content: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}
+  labels:
+    {{- include "` + name + `.labels" . | nindent 4 }}
+spec:
+  replicas: {{ .Values.replicaCount }}
+  selector:
+    matchLabels:
+      {{- include "` + name + `.selectorLabels" . | nindent 6 }}
+  template:
+    metadata:
+      labels:
+        {{- include "` + name + `.selectorLabels" . | nindent 8 }}
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile:
+          type: RuntimeDefault
+      containers:
+        - name: {{ .Chart.Name }}
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+          imagePullPolicy: {{ .Values.image.pullPolicy | default "IfNotPresent" }}
+          ports:
+            - name: http
+              containerPort: 8080
+          readinessProbe:
+            httpGet:
+              path: /
+              port: http
+          resources:
+            {{- toYaml .Values.resources | nindent 12 }}
+          securityContext:
+            allowPrivilegeEscalation: false
+            readOnlyRootFilesystem: true
+            capabilities:
+              drop: [ALL]
+          volumeMounts:
+            - name: tmp
+              mountPath: /tmp
+      volumes:
+        - name: tmp
+          emptyDir: {}
+`},
+		{Path: dir + "templates/service.yaml", Content: `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Release.Name }}
+  labels:
+    {{- include "` + name + `.labels" . | nindent 4 }}
+spec:
+  type: {{ .Values.service.type | default "ClusterIP" }}
+  selector:
+    {{- include "` + name + `.selectorLabels" . | nindent 4 }}
+  ports:
+    - name: http
+      port: {{ .Values.service.port }}
+      targetPort: http
+`},
+	}
+}
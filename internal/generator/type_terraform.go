@@ -0,0 +1,233 @@
+package generator
+
+import (
+	"context"
+)
+
+// Versions pinned in the generated configuration and pre-commit hooks.
+const (
+	terraformRandomVersion   = "3.7.1"
+	preCommitTerraformRev    = "v1.99.0"
+	terraformRequiredVersion = ">= 1.6, < 2.0"
+)
+
+var terraformEnvironments = []string{"dev", "staging", "prod"}
+
+// terraformDenyRules deny Claude Code running terraform apply and destroy,
+// directly and with -chdir into each of dirs. Bash rules match on command
+// prefixes, so other spellings still get through.
+func terraformDenyRules(dirs ...string) []string {
+	var rules []string
+	for _, command := range []string{"apply", "destroy"} {
+		rules = append(rules, "Bash(terraform "+command+":*)")
+		for _, dir := range dirs {
+			rules = append(rules, "Bash(terraform -chdir="+dir+" "+command+":*)")
+		}
+	}
+	return rules
+}
+
+func terraformEnvironmentDirs() []string {
+	dirs := make([]string, 0, len(terraformEnvironments))
+	for _, env := range terraformEnvironments {
+		dirs = append(dirs, "environments/"+env)
+	}
+	return dirs
+}
+
+func init() {
+	mustRegisterProjectType(terraformType())
+}
+
+func terraformType() ProjectType {
+	return ProjectType{
+		Name:        "terraform",
+		Description: "Terraform modules with dev, staging and prod environments; Claude may plan but never apply",
+		IssueFields: []IssueField{
+			{ID: "terraform-version", Label: "Terraform version", Description: "Output of `terraform version`", Placeholder: "Terraform v1.9.8", Required: true},
+			{ID: "environment", Label: "Environment", Options: terraformEnvironments, Required: true},
+		},
+		Stacks: []string{StackTerraform},
+		MakeVars: []string{
+			"ENV ?= dev",
+			"DIR := environments/$(ENV)",
+		},
+		MakeTargets: []MakeTarget{
+			{Name: "install", Help: "Initialize ENV (default dev) with its backend", Recipe: []string{
+				`@test -d $(DIR) || (echo "Unknown ENV $(ENV): expected dev, staging or prod"; exit 1)`,
+				"cd $(DIR) && terraform init -backend-config=backend.hcl",
+			}},
+			{Name: "dev", Help: "Plan ENV (alias for make plan)", Recipe: []string{"@$(MAKE) plan"}},
+			{Name: "plan", Help: "Plan ENV and save it to tfplan", Recipe: []string{
+				`@test -d $(DIR) || (echo "Unknown ENV $(ENV): expected dev, staging or prod"; exit 1)`,
+				"cd $(DIR) && terraform plan -out=tfplan",
+			}},
+			{Name: "test", Help: "Validate every environment without a backend", Recipe: []string{
+				`@for dir in environments/*/; do echo "Validating $$dir"; (cd $$dir && terraform init -backend=false -input=false >/dev/null && terraform validate) || exit 1; done`,
+			}},
+			{Name: "lint", Help: "Check formatting and run tflint", Recipe: []string{
+				"terraform fmt -check -recursive",
+				"@if command -v tflint >/dev/null; then tflint --init && tflint --recursive; else echo \"tflint not installed, skipping\"; fi",
+			}},
+			{Name: "format", Help: "Format all Terraform files", Recipe: []string{"terraform fmt -recursive"}},
+			{Name: "docs", Help: "Update module READMEs with terraform-docs", Recipe: []string{
+				"@for dir in modules/*/; do terraform-docs markdown table --output-file README.md --output-mode inject $$dir; done",
+			}},
+			{Name: "build", Help: "Validate every environment (alias for make test)", Recipe: []string{"@$(MAKE) test"}},
+			{Name: "clean", Help: "Remove local Terraform caches and saved plans", Recipe: []string{
+				"rm -rf environments/*/.terraform environments/*/tfplan modules/*/.terraform",
+			}},
+		},
+		GitIgnore: []string{
+			".terraform/",
+			"*.tfstate",
+			"*.tfstate.*",
+			"tfplan",
+			"crash.log",
+			"override.tf",
+			"*_override.tf",
+		},
+		PreCommit: `  - repo: https://github.com/antonbabenko/pre-commit-terraform
+    rev: ` + preCommitTerraformRev + `
+    hooks:
+      - id: terraform_fmt
+      - id: terraform_validate
+      - id: terraform_tflint
+      - id: terraform_docs
+`,
+		Permissions: Permissions{
+			Allow: []string{
+				"Bash(terraform fmt:*)",
+				"Bash(terraform init:*)",
+				"Bash(terraform validate:*)",
+				"Bash(terraform plan:*)",
+				"Bash(make plan:*)",
+				"Bash(make test)",
+				"Bash(make lint)",
+			},
+			Deny: terraformDenyRules(terraformEnvironmentDirs()...),
+		},
+		Claude: `## Terraform Project
+
+- ` + "`modules/`" + ` - Reusable modules; each has a README with terraform-docs markers
+- ` + "`environments/{dev,staging,prod}/`" + ` - Root configurations calling the modules
+- ` + "`environments/*/backend.hcl`" + ` - Backend settings, passed to ` + "`terraform init -backend-config`" + `
+- ` + "`environments/*/versions.tf`" + ` - Pinned Terraform and provider versions
+
+Make targets take an ` + "`ENV`" + ` variable (default ` + "`dev`" + `): ` + "`make plan ENV=staging`" + `.
+
+**Never run ` + "`terraform apply`" + ` or ` + "`terraform destroy`" + `**, in any environment, however
+the command is spelled. ` + "`.claude/settings.json`" + ` denies the usual forms, but those rules
+match command prefixes and are a backstop, not a hard guard. Claude may format,
+validate and plan; a human reviews the plan and applies it.
+
+Conventions:
+
+- Modules declare provider requirements with lower bounds; environments pin exact versions
+- Every variable and output has a ` + "`description`" + `, and variables have a ` + "`type`" + `
+- Run ` + "`make format lint test`" + ` before committing, and ` + "`make docs`" + ` after changing a module's interface
+- Commit ` + "`.terraform.lock.hcl`" + ` files; never commit state or ` + "`tfplan`" + ` files
+`,
+		Generators: []FileGenerator{generateTerraformFiles},
+	}
+}
+
+func generateTerraformFiles(ctx context.Context, config *ProjectConfig) ([]File, error) {
+	name := config.Name
+
+	files := []File{
+		{Path: ".tflint.hcl", Content: `config {
+  call_module_type = "local"
+}
+
+plugin "terraform" {
+  enabled = true
+  preset  = "recommended"
+}
+`},
+		{Path: "modules/example/main.tf", Content: `resource "random_pet" "this" {
+  prefix = "${var.name}-${var.environment}"
+}
+`},
+		{Path: "modules/example/variables.tf", Content: `variable "name" {
+  description = "Name prefix for resources."
+  type        = string
+}
+
+variable "environment" {
+  description = "Deployment environment."
+  type        = string
+
+  validation {
+    condition     = contains(["dev", "staging", "prod"], var.environment)
+    error_message = "environment must be dev, staging or prod."
+  }
+}
+`},
+		{Path: "modules/example/outputs.tf", Content: `output "id" {
+  description = "Generated resource name."
+  value       = random_pet.this.id
+}
+`},
+		{Path: "modules/example/versions.tf", Content: `terraform {
+  required_version = "` + terraformRequiredVersion + `"
+
+  required_providers {
+    random = {
+      source  = "hashicorp/random"
+      version = ">= 3.6"
+    }
+  }
+}
+`},
+		{Path: "modules/example/README.md", Content: `# example
+
+An example module. Replace it with your own.
+
+<!-- BEGIN_TF_DOCS -->
+<!-- END_TF_DOCS -->
+`},
+	}
+
+	for _, env := range terraformEnvironments {
+		dir := "environments/" + env + "/"
+		files = append(files,
+			File{Path: dir + "main.tf", Content: `module "example" {
+  source = "../../modules/example"
+
+  name        = "` + name + `"
+  environment = "` + env + `"
+}
+
+output "example_id" {
+  description = "Generated resource name."
+  value       = module.example.id
+}
+`},
+			File{Path: dir + "versions.tf", Content: `terraform {
+  required_version = "` + terraformRequiredVersion + `"
+
+  required_providers {
+    random = {
+      source  = "hashicorp/random"
+      version = "` + terraformRandomVersion + `"
+    }
+  }
+}
+`},
+			File{Path: dir + "backend.tf", Content: `terraform {
+  # Partial configuration: the settings are in backend.hcl.
+  # Replace s3 with the backend you use.
+  backend "s3" {}
+}
+`},
+			File{Path: dir + "backend.hcl", Content: `# Backend settings for ` + env + `, used by: terraform init -backend-config=backend.hcl
+bucket = "CHANGE-ME-terraform-state"
+key    = "` + name + `/` + env + `/terraform.tfstate"
+region = "us-east-1"
+`},
+		)
+	}
+
+	return files, nil
+}
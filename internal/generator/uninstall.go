@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// UninstallResult lists what Uninstall did, or would do in a dry run. Paths
+// are relative to the project root.
+type UninstallResult struct {
+	Removed  []string
+	Stripped []string
+	Modified []string
+	Missing  []string
+}
+
+// Uninstall removes the files recorded in the manifest. Files that are
+// unchanged since generation are deleted and cc managed blocks are stripped
+// from merged files; files the user has modified are kept and reported unless
+// force is set. Like InitializeProject, a failure rolls everything back.
+func (g *Generator) Uninstall(root string, force, dryRun bool) (result *UninstallResult, err error) {
+	manifest, err := g.LoadManifest(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Files) == 0 {
+		return nil, fmt.Errorf("nothing to uninstall: %s not found", ManifestFile)
+	}
+
+	g.journal = newJournal(g.FS)
+	g.root = root
+	defer func() {
+		if err != nil {
+			if rbErr := g.journal.rollback(); rbErr != nil {
+				err = fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+			}
+		}
+	}()
+
+	paths := make([]string, 0, len(manifest.Files))
+	for rel := range manifest.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	result = &UninstallResult{}
+	dirs := make(map[string]bool)
+
+	for _, rel := range paths {
+		entry := manifest.Files[rel]
+		path := filepath.Join(root, filepath.FromSlash(rel))
+
+		content, current, ok, err := g.readTracked(root, rel, entry)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			result.Missing = append(result.Missing, rel)
+			delete(manifest.Files, rel)
+			continue
+		}
+
+		if hashContent(current) != entry.SHA256 && !force {
+			result.Modified = append(result.Modified, rel)
+			continue
+		}
+		delete(manifest.Files, rel)
+
+		remaining := ""
+		if entry.Block {
+			remaining = stripBlock(content)
+		}
+
+		if strings.TrimSpace(remaining) != "" {
+			result.Stripped = append(result.Stripped, rel)
+			if !dryRun {
+				if err := g.put(path, []byte(remaining)); err != nil {
+					return nil, fmt.Errorf("failed to update %s: %w", rel, err)
+				}
+			}
+			continue
+		}
+
+		result.Removed = append(result.Removed, rel)
+		for dir := filepath.Dir(rel); dir != "." && dir != "/"; dir = filepath.Dir(dir) {
+			dirs[dir] = true
+		}
+		if !dryRun {
+			if err := g.remove(path); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+			}
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Keep the manifest around for files that were left in place, so a later
+	// uninstall --force can still find them
+	manifestPath := filepath.Join(root, ManifestFile)
+	if len(manifest.Files) > 0 {
+		data, err := manifest.marshal()
+		if err != nil {
+			return nil, err
+		}
+		if err := g.put(manifestPath, data); err != nil {
+			return nil, fmt.Errorf("failed to update manifest: %w", err)
+		}
+	} else {
+		if err := g.remove(manifestPath); err != nil {
+			return nil, fmt.Errorf("failed to remove manifest: %w", err)
+		}
+		if err := g.removeTree(filepath.Join(root, BackupDir)); err != nil {
+			return nil, fmt.Errorf("failed to remove backups: %w", err)
+		}
+		dirs[filepath.Dir(ManifestFile)] = true
+	}
+
+	// Remove directories left empty, deepest first
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return strings.Count(sorted[i], "/") > strings.Count(sorted[j], "/")
+	})
+	for _, dir := range sorted {
+		if err := g.removeEmptyDir(filepath.Join(root, filepath.FromSlash(dir))); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
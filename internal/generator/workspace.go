@@ -0,0 +1,648 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace is a monorepo found in the project root: the tools that define it
+// and the packages they list.
+type Workspace struct {
+	// Tools are the workspace definitions found, such as go.work or Cargo
+	// workspace, or "directories" when packages were found by their
+	// manifests alone
+	Tools    []string
+	Packages []Package
+}
+
+// Package is a project inside a workspace.
+type Package struct {
+	Path string // relative to the root and slash separated
+	Name string
+	Kind string // go, node, rust or python
+	// Slug names the package's targets in the root Makefile (test-<slug>)
+	Slug string
+	// Commands run from the package directory, in the order test, lint, build
+	Commands []PackageCommand
+}
+
+// PackageCommand is how a package runs one of the fanned out Makefile targets.
+type PackageCommand struct {
+	Target  string
+	Command string
+}
+
+// packageManifests identify a directory as a package, in order of precedence.
+var packageManifests = []struct {
+	file string
+	kind string
+}{
+	{"go.mod", "go"},
+	{"Cargo.toml", "rust"},
+	{"package.json", "node"},
+	{"pyproject.toml", "python"},
+}
+
+// workspaceSkipDirs are never searched for packages.
+var workspaceSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"testdata":     true,
+}
+
+// detectWorkspace finds the packages of a monorepo from its go.work, npm or
+// pnpm workspaces, Cargo workspace or uv workspace. Without any of those, two
+// or more directories with their own manifests also make a workspace. It
+// returns nil for a single project.
+func (g *Generator) detectWorkspace(root string) (*Workspace, error) {
+	ws := &Workspace{}
+	var patterns []string
+	var excludes []string
+
+	add := func(tool string, members, excluded []string) {
+		if len(members) == 0 {
+			return
+		}
+		ws.Tools = appendUnique(ws.Tools, tool)
+		patterns = append(patterns, members...)
+		excludes = append(excludes, excluded...)
+	}
+
+	if content, exists, err := g.readDisk(filepath.Join(root, "go.work")); err != nil {
+		return nil, err
+	} else if exists {
+		add("go.work", goWorkUses(content), nil)
+	}
+
+	if content, exists, err := g.readDisk(filepath.Join(root, "pnpm-workspace.yaml")); err != nil {
+		return nil, err
+	} else if exists {
+		var config struct {
+			Packages []string `yaml:"packages"`
+		}
+		if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+			return nil, fmt.Errorf("failed to parse pnpm-workspace.yaml: %w", err)
+		}
+		members, excluded := splitNegated(config.Packages)
+		add("pnpm workspaces", members, excluded)
+	}
+
+	if content, exists, err := g.readDisk(filepath.Join(root, "package.json")); err != nil {
+		return nil, err
+	} else if exists {
+		members, err := npmWorkspaces(content)
+		if err != nil {
+			return nil, err
+		}
+		members, excluded := splitNegated(members)
+		add(g.nodePackageManager(root)+" workspaces", members, excluded)
+	}
+
+	if content, exists, err := g.readDisk(filepath.Join(root, "Cargo.toml")); err != nil {
+		return nil, err
+	} else if exists {
+		var config struct {
+			Workspace struct {
+				Members []string `toml:"members"`
+				Exclude []string `toml:"exclude"`
+			} `toml:"workspace"`
+		}
+		if err := toml.Unmarshal([]byte(content), &config); err != nil {
+			return nil, fmt.Errorf("failed to parse Cargo.toml: %w", err)
+		}
+		add("Cargo workspace", config.Workspace.Members, config.Workspace.Exclude)
+	}
+
+	if content, exists, err := g.readDisk(filepath.Join(root, "pyproject.toml")); err != nil {
+		return nil, err
+	} else if exists {
+		var config struct {
+			Tool struct {
+				UV struct {
+					Workspace struct {
+						Members []string `toml:"members"`
+						Exclude []string `toml:"exclude"`
+					} `toml:"workspace"`
+				} `toml:"uv"`
+			} `toml:"tool"`
+		}
+		if err := toml.Unmarshal([]byte(content), &config); err != nil {
+			return nil, fmt.Errorf("failed to parse pyproject.toml: %w", err)
+		}
+		add("uv workspace", config.Tool.UV.Workspace.Members, config.Tool.UV.Workspace.Exclude)
+	}
+
+	var dirs []string
+	if len(patterns) > 0 {
+		excluded := make(map[string]bool)
+		for _, pattern := range excludes {
+			matches, err := g.globDirs(root, pattern)
+			if err != nil {
+				return nil, err
+			}
+			for _, dir := range matches {
+				excluded[dir] = true
+			}
+		}
+		for _, pattern := range patterns {
+			matches, err := g.globDirs(root, pattern)
+			if err != nil {
+				return nil, err
+			}
+			for _, dir := range matches {
+				if !excluded[dir] && !contains(dirs, dir) {
+					dirs = append(dirs, dir)
+				}
+			}
+		}
+	} else {
+		found, err := g.findPackageDirs(root, "", 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(found) < 2 {
+			return nil, nil
+		}
+		ws.Tools = []string{"directories"}
+		dirs = found
+	}
+
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		pkg, ok, err := g.readPackage(root, dir)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ws.Packages = append(ws.Packages, pkg)
+		}
+	}
+	if len(ws.Packages) == 0 {
+		return nil, nil
+	}
+	assignPackageSlugs(ws.Packages)
+
+	return ws, nil
+}
+
+var goWorkUseRe = regexp.MustCompile(`^use\s+(\S+)$`)
+
+// goWorkUses returns the directories in a go.work file's use directives.
+func goWorkUses(content string) []string {
+	var uses []string
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			uses = append(uses, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		default:
+			if match := goWorkUseRe.FindStringSubmatch(line); match != nil {
+				uses = append(uses, strings.Trim(match[1], `"`))
+			}
+		}
+	}
+	return uses
+}
+
+// npmWorkspaces returns the workspaces of a package.json, given either as a
+// list or, as yarn allows, as an object with a packages list.
+func npmWorkspaces(content string) ([]string, error) {
+	var config struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return nil, fmt.Errorf("failed to parse package.json: %w", err)
+	}
+	if len(config.Workspaces) == 0 {
+		return nil, nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(config.Workspaces, &list); err == nil {
+		return list, nil
+	}
+	var object struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(config.Workspaces, &object); err != nil {
+		return nil, fmt.Errorf("failed to parse workspaces in package.json: %w", err)
+	}
+	return object.Packages, nil
+}
+
+// splitNegated separates "!pattern" exclusions from the other patterns.
+func splitNegated(patterns []string) (include, exclude []string) {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			exclude = append(exclude, strings.TrimPrefix(pattern, "!"))
+		} else {
+			include = append(include, pattern)
+		}
+	}
+	return include, exclude
+}
+
+// nodePackageManager judges the package manager from the lockfile in root.
+func (g *Generator) nodePackageManager(root string) string {
+	switch {
+	case g.onDisk(filepath.Join(root, "pnpm-lock.yaml")):
+		return "pnpm"
+	case g.onDisk(filepath.Join(root, "yarn.lock")):
+		return "yarn"
+	}
+	return "npm"
+}
+
+// globDirs expands a workspace member pattern to the directories under root
+// it matches. Each path segment may use path.Match syntax, and a ** segment
+// matches any number of directories.
+func (g *Generator) globDirs(root, pattern string) ([]string, error) {
+	pattern = strings.TrimPrefix(path.Clean(strings.TrimPrefix(filepath.ToSlash(pattern), "./")), "/")
+	if pattern == "." || pattern == ".." || strings.HasPrefix(pattern, "../") {
+		return nil, nil
+	}
+
+	var matches []string
+	var expand func(dir string, segments []string) error
+	expand = func(dir string, segments []string) error {
+		if len(segments) == 0 {
+			if dir != "" {
+				matches = append(matches, dir)
+			}
+			return nil
+		}
+
+		segment := segments[0]
+		if !strings.ContainsAny(segment, "*?[") {
+			next := path.Join(dir, segment)
+			if info, err := g.FS.Stat(filepath.Join(root, filepath.FromSlash(next))); err != nil || !info.IsDir() {
+				return nil
+			}
+			return expand(next, segments[1:])
+		}
+
+		if segment == "**" {
+			if err := expand(dir, segments[1:]); err != nil {
+				return err
+			}
+		}
+		subdirs, err := g.subdirs(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return err
+		}
+		for _, name := range subdirs {
+			next := path.Join(dir, name)
+			if segment == "**" {
+				if err := expand(next, segments); err != nil {
+					return err
+				}
+				continue
+			}
+			if ok, err := path.Match(segment, name); err != nil {
+				return fmt.Errorf("invalid workspace pattern %q: %w", pattern, err)
+			} else if ok {
+				if err := expand(next, segments[1:]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := expand("", strings.Split(pattern, "/")); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// subdirs lists the directories in dir that may hold packages.
+func (g *Generator) subdirs(dir string) ([]string, error) {
+	entries, err := g.FS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && !strings.HasPrefix(name, ".") && !workspaceSkipDirs[name] {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// findPackageDirs returns the directories below dir, up to depth levels deep,
+// that have a package manifest. Packages are not searched for nested ones.
+func (g *Generator) findPackageDirs(root, dir string, depth int) ([]string, error) {
+	if depth == 0 {
+		return nil, nil
+	}
+
+	subdirs, err := g.subdirs(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	for _, name := range subdirs {
+		next := path.Join(dir, name)
+		if g.packageKind(root, next) != "" {
+			found = append(found, next)
+			continue
+		}
+		nested, err := g.findPackageDirs(root, next, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, nested...)
+	}
+	return found, nil
+}
+
+func (g *Generator) onDisk(path string) bool {
+	_, err := g.FS.Stat(path)
+	return err == nil
+}
+
+func (g *Generator) packageKind(root, dir string) string {
+	for _, manifest := range packageManifests {
+		if g.onDisk(filepath.Join(root, filepath.FromSlash(dir), manifest.file)) {
+			return manifest.kind
+		}
+	}
+	return ""
+}
+
+// readPackage describes the package in dir from its manifest. Directories
+// without a manifest are not packages.
+func (g *Generator) readPackage(root, dir string) (Package, bool, error) {
+	kind := g.packageKind(root, dir)
+	if kind == "" {
+		return Package{}, false, nil
+	}
+
+	pkgPath := filepath.Join(root, filepath.FromSlash(dir))
+	pkg := Package{Path: dir, Name: path.Base(dir), Kind: kind}
+
+	var commands [][2]string
+	switch kind {
+	case "go":
+		content, _, err := g.readDisk(filepath.Join(pkgPath, "go.mod"))
+		if err != nil {
+			return Package{}, false, err
+		}
+		if module := goModulePath(content); module != "" {
+			pkg.Name = module
+		}
+		commands = [][2]string{{"test", "go test ./..."}, {"lint", "go vet ./..."}, {"build", "go build ./..."}}
+
+	case "rust":
+		content, _, err := g.readDisk(filepath.Join(pkgPath, "Cargo.toml"))
+		if err != nil {
+			return Package{}, false, err
+		}
+		var manifest struct {
+			Package struct {
+				Name string `toml:"name"`
+			} `toml:"package"`
+		}
+		if err := toml.Unmarshal([]byte(content), &manifest); err != nil {
+			return Package{}, false, fmt.Errorf("failed to parse %s/Cargo.toml: %w", dir, err)
+		}
+		if manifest.Package.Name != "" {
+			pkg.Name = manifest.Package.Name
+		}
+		commands = [][2]string{{"test", "cargo test"}, {"lint", "cargo clippy -- -D warnings"}, {"build", "cargo build"}}
+
+	case "node":
+		content, _, err := g.readDisk(filepath.Join(pkgPath, "package.json"))
+		if err != nil {
+			return Package{}, false, err
+		}
+		var manifest struct {
+			Name    string            `json:"name"`
+			Scripts map[string]string `json:"scripts"`
+		}
+		if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+			return Package{}, false, fmt.Errorf("failed to parse %s/package.json: %w", dir, err)
+		}
+		if manifest.Name != "" {
+			pkg.Name = manifest.Name
+		}
+		// Only scripts the package defines can be run
+		manager := g.nodePackageManager(root)
+		for _, target := range []string{"test", "lint", "build"} {
+			if _, ok := manifest.Scripts[target]; ok {
+				commands = append(commands, [2]string{target, manager + " run " + target})
+			}
+		}
+
+	case "python":
+		content, _, err := g.readDisk(filepath.Join(pkgPath, "pyproject.toml"))
+		if err != nil {
+			return Package{}, false, err
+		}
+		var manifest struct {
+			Project struct {
+				Name string `toml:"name"`
+			} `toml:"project"`
+		}
+		if err := toml.Unmarshal([]byte(content), &manifest); err != nil {
+			return Package{}, false, fmt.Errorf("failed to parse %s/pyproject.toml: %w", dir, err)
+		}
+		if manifest.Project.Name != "" {
+			pkg.Name = manifest.Project.Name
+		}
+		commands = [][2]string{{"test", "uv run pytest"}, {"lint", "uv run ruff check ."}, {"build", "uv build"}}
+	}
+
+	// A package's own Makefile knows best how to run its targets
+	makefile, _, err := g.readDisk(filepath.Join(pkgPath, "Makefile"))
+	if err != nil {
+		return Package{}, false, err
+	}
+	defined := definedMakeTargets(makefile)
+	for _, command := range commands {
+		if defined[command[0]] {
+			command[1] = "make " + command[0]
+		}
+		pkg.Commands = append(pkg.Commands, PackageCommand{Target: command[0], Command: command[1]})
+	}
+
+	return pkg, true, nil
+}
+
+var goModDirectiveRe = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+func goModulePath(goMod string) string {
+	if match := goModDirectiveRe.FindStringSubmatch(goMod); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+var packageSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// assignPackageSlugs names packages after their directory, or after their
+// whole path when two directories share a name.
+func assignPackageSlugs(packages []Package) {
+	count := make(map[string]int)
+	for _, pkg := range packages {
+		count[path.Base(pkg.Path)]++
+	}
+	for i, pkg := range packages {
+		name := path.Base(pkg.Path)
+		if count[name] > 1 {
+			name = pkg.Path
+		}
+		packages[i].Slug = strings.Trim(packageSlugRe.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	}
+}
+
+// workspaceMakeTargets fans test, lint and build out to every package that
+// supports them, with a target per package such as test-api.
+func workspaceMakeTargets(ws *Workspace) []MakeTarget {
+	if ws == nil {
+		return nil
+	}
+
+	helps := map[string][2]string{
+		"test":  {"Run tests in every package", "Run tests in %s"},
+		"lint":  {"Run linting in every package", "Run linting in %s"},
+		"build": {"Build every package", "Build %s"},
+	}
+
+	var aggregates, targets []MakeTarget
+	for _, target := range []string{"test", "lint", "build"} {
+		var names []string
+		for _, pkg := range ws.Packages {
+			for _, command := range pkg.Commands {
+				if command.Target != target {
+					continue
+				}
+				recipe := "cd " + pkg.Path + " && " + command.Command
+				if command.Command == "make "+target {
+					recipe = "$(MAKE) -C " + pkg.Path + " " + target
+				}
+				name := target + "-" + pkg.Slug
+				names = append(names, name)
+				targets = append(targets, MakeTarget{Name: name, Help: fmt.Sprintf(helps[target][1], pkg.Path), Recipe: []string{recipe}})
+			}
+		}
+		if len(names) > 0 {
+			aggregates = append(aggregates, MakeTarget{Name: target, Help: helps[target][0], Recipe: []string{"@$(MAKE) " + strings.Join(names, " ")}})
+		}
+	}
+
+	return append(aggregates, targets...)
+}
+
+var packageKindLabels = map[string]string{
+	"go":     "Go",
+	"node":   "Node",
+	"rust":   "Rust",
+	"python": "Python",
+}
+
+// workspaceNotes is the workspace map in the root CLAUDE.md.
+func workspaceNotes(ws *Workspace) string {
+	if ws == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("## Workspace\n\n")
+	if contains(ws.Tools, "directories") {
+		b.WriteString("This repository holds several packages, each with its own manifest.")
+	} else {
+		fmt.Fprintf(&b, "This repository is a monorepo (%s).", strings.Join(ws.Tools, ", "))
+	}
+	b.WriteString(`
+Each package has its own CLAUDE.md with its commands. Work inside one package
+at a time, and run its commands from its directory or from the root with
+` + "`make test-<package>`" + `. ` + "`make test`" + `, ` + "`make lint`" + ` and ` + "`make build`" + ` run every package.
+
+| Package | Path | Kind | Make targets |
+|---------|------|------|--------------|
+`)
+	for _, pkg := range ws.Packages {
+		var targets []string
+		for _, command := range pkg.Commands {
+			targets = append(targets, "`"+command.Target+"-"+pkg.Slug+"`")
+		}
+		fmt.Fprintf(&b, "| %s | `%s/` | %s | %s |\n", pkg.Name, pkg.Path, packageKindLabels[pkg.Kind], strings.Join(targets, ", "))
+	}
+
+	return b.String()
+}
+
+// generatePackageClaudeMDs writes a CLAUDE.md into every workspace package
+// with the commands for that package.
+func (g *Generator) generatePackageClaudeMDs(projectPath string, config *ProjectConfig) error {
+	if g.workspace == nil {
+		return nil
+	}
+
+	for _, pkg := range g.workspace.Packages {
+		up := strings.Repeat("../", strings.Count(pkg.Path, "/")+1)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", pkg.Name)
+		fmt.Fprintf(&b, "%s package in the %s workspace. The root [CLAUDE.md](%sCLAUDE.md)\n", packageKindLabels[pkg.Kind], config.Name, up)
+		b.WriteString("describes the whole repository; this file covers only `" + pkg.Path + "/`.\n")
+
+		if len(pkg.Commands) > 0 {
+			width := 0
+			for _, command := range pkg.Commands {
+				width = max(width, len(command.Command))
+			}
+			comments := map[string]string{"test": "Run tests", "lint": "Run linting", "build": "Build the package"}
+
+			b.WriteString("\n## Commands\n\nRun from this directory:\n\n```bash\n")
+			for _, command := range pkg.Commands {
+				fmt.Fprintf(&b, "%-*s  # %s\n", width, command.Command, comments[command.Target])
+			}
+			b.WriteString("```\n\nFrom the repository root:\n\n```bash\n")
+			for _, command := range pkg.Commands {
+				fmt.Fprintf(&b, "make %s-%s\n", command.Target, pkg.Slug)
+			}
+			b.WriteString("```\n")
+		}
+
+		b.WriteString(`
+## Conventions
+
+- Keep changes inside this package unless the task needs another one
+- Changes that affect other packages must update them in the same commit
+- Run this package's tests before committing, and ` + "`make test`" + ` at the root
+  when changing code other packages depend on
+`)
+
+		if err := g.writeFile(filepath.Join(projectPath, filepath.FromSlash(pkg.Path), "CLAUDE.md"), b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
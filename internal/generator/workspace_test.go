@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectWorkspace(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		tools    []string
+		packages []string // path=kind:name
+	}{
+		{
+			name:  "single project",
+			files: map[string]string{"go.mod": "module example.com/app\n", "cmd/app/main.go": "package main\n"},
+		},
+		{
+			name: "go.work",
+			files: map[string]string{
+				"go.work":                "go 1.24\n\nuse ./tools\n\nuse (\n\t./services/api // the API\n\t\"./services/worker\"\n)\n",
+				"tools/go.mod":           "module example.com/tools\n",
+				"services/api/go.mod":    "module example.com/api\n",
+				"services/worker/go.mod": "module example.com/worker\n",
+			},
+			tools:    []string{"go.work"},
+			packages: []string{"services/api=go:example.com/api", "services/worker=go:example.com/worker", "tools=go:example.com/tools"},
+		},
+		{
+			name: "npm workspaces with exclusion",
+			files: map[string]string{
+				"package.json":                 `{"workspaces": {"packages": ["packages/*", "!packages/legacy"]}}`,
+				"yarn.lock":                    "",
+				"packages/ui/package.json":     `{"name": "@acme/ui", "scripts": {"test": "vitest"}}`,
+				"packages/legacy/package.json": `{"name": "legacy"}`,
+				"packages/docs/README.md":      "# Docs\n",
+			},
+			tools:    []string{"yarn workspaces"},
+			packages: []string{"packages/ui=node:@acme/ui"},
+		},
+		{
+			name: "pnpm workspaces with nested globs",
+			files: map[string]string{
+				"pnpm-workspace.yaml":          "packages:\n  - 'apps/**'\n",
+				"pnpm-lock.yaml":               "",
+				"apps/web/package.json":        `{"name": "web"}`,
+				"apps/admin/site/package.json": `{"name": "admin-site"}`,
+			},
+			tools:    []string{"pnpm workspaces"},
+			packages: []string{"apps/admin/site=node:admin-site", "apps/web=node:web"},
+		},
+		{
+			name: "cargo and uv workspaces",
+			files: map[string]string{
+				"Cargo.toml":                "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/scratch\"]\n",
+				"crates/core/Cargo.toml":    "[package]\nname = \"acme-core\"\n",
+				"crates/scratch/Cargo.toml": "[package]\nname = \"scratch\"\n",
+				"pyproject.toml":            "[tool.uv.workspace]\nmembers = [\"python/*\"]\n",
+				"python/etl/pyproject.toml": "[project]\nname = \"etl\"\n",
+			},
+			tools:    []string{"Cargo workspace", "uv workspace"},
+			packages: []string{"crates/core=rust:acme-core", "python/etl=python:etl"},
+		},
+		{
+			name: "directories with manifests",
+			files: map[string]string{
+				"backend/go.mod":                       "module example.com/backend\n",
+				"frontend/package.json":                `{"name": "frontend"}`,
+				"frontend/node_modules/x/package.json": `{"name": "x"}`,
+				".dagger/go.mod":                       "module dagger/example\n",
+			},
+			tools:    []string{"directories"},
+			packages: []string{"backend=go:example.com/backend", "frontend=node:frontend"},
+		},
+		{
+			name:  "one directory with a manifest",
+			files: map[string]string{"docs/package.json": `{"name": "docs"}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := newTestGenerator(AferoFS{Fs: newMemFS(t, tt.files)})
+
+			ws, err := gen.detectWorkspace(testRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.tools == nil {
+				if ws != nil {
+					t.Fatalf("workspace = %+v, want none", ws)
+				}
+				return
+			}
+			if ws == nil {
+				t.Fatal("no workspace detected")
+			}
+
+			var packages []string
+			for _, pkg := range ws.Packages {
+				packages = append(packages, pkg.Path+"="+pkg.Kind+":"+pkg.Name)
+			}
+			if !reflect.DeepEqual(ws.Tools, tt.tools) {
+				t.Errorf("tools = %q, want %q", ws.Tools, tt.tools)
+			}
+			if !reflect.DeepEqual(packages, tt.packages) {
+				t.Errorf("packages = %q, want %q", packages, tt.packages)
+			}
+		})
+	}
+}
+
+func TestWorkspaceMakeTargets(t *testing.T) {
+	ws := &Workspace{Packages: []Package{
+		{Path: "api", Commands: []PackageCommand{{"test", "go test ./..."}, {"lint", "make lint"}}},
+		{Path: "web/api", Commands: []PackageCommand{{"test", "npm run test"}}},
+	}}
+	assignPackageSlugs(ws.Packages)
+
+	got := make(map[string][]string)
+	var names []string
+	for _, target := range workspaceMakeTargets(ws) {
+		names = append(names, target.Name)
+		got[target.Name] = target.Recipe
+	}
+
+	wantNames := []string{"test", "lint", "test-api", "test-web-api", "lint-api"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("targets = %q, want %q", names, wantNames)
+	}
+	want := map[string]string{
+		"test":         "@$(MAKE) test-api test-web-api",
+		"lint":         "@$(MAKE) lint-api",
+		"test-api":     "cd api && go test ./...",
+		"test-web-api": "cd web/api && npm run test",
+		"lint-api":     "$(MAKE) -C api lint",
+	}
+	for name, recipe := range want {
+		if len(got[name]) != 1 || got[name][0] != recipe {
+			t.Errorf("%s recipe = %q, want %q", name, got[name], recipe)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package gitutil runs the local git commands cc needs. It never talks to a
+// remote.
+package gitutil
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Repo is a git working tree. Paths passed to its methods are relative to Dir,
+// which may be a subdirectory of the repository.
+type Repo struct {
+	Dir string
+}
+
+// Open returns the repository containing dir, or an error if dir is not inside
+// a git working tree or git is not installed.
+func Open(dir string) (*Repo, error) {
+	repo := &Repo{Dir: dir}
+	if _, err := repo.run("rev-parse", "--is-inside-work-tree"); err != nil {
+		return nil, fmt.Errorf("%s is not a git repository: %w", dir, err)
+	}
+	return repo, nil
+}
+
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.Dir}, args...)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
+
+// Changes returns the porcelain status lines for uncommitted changes,
+// including untracked files. A clean tree has none.
+func (r *Repo) Changes() ([]string, error) {
+	out, err := r.run("status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			changes = append(changes, line)
+		}
+	}
+	return changes, nil
+}
+
+// CurrentBranch returns the checked out branch name.
+func (r *Repo) CurrentBranch() (string, error) {
+	out, err := r.run("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CreateBranch creates name from HEAD and checks it out.
+func (r *Repo) CreateBranch(name string) error {
+	_, err := r.run("checkout", "-b", name)
+	return err
+}
+
+// AbandonBranch checks out what was checked out before name was created and
+// deletes name, undoing CreateBranch.
+func (r *Repo) AbandonBranch(name string) error {
+	if _, err := r.run("checkout", "-q", "@{-1}"); err != nil {
+		return err
+	}
+	_, err := r.run("branch", "-D", name)
+	return err
+}
+
+// Add stages the given paths.
+func (r *Repo) Add(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, err := r.run(append([]string{"add", "--"}, paths...)...)
+	return err
+}
+
+// Unstage resets the given paths in the index to HEAD, undoing Add.
+func (r *Repo) Unstage(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	_, err := r.run(append([]string{"reset", "-q", "--"}, paths...)...)
+	return err
+}
+
+// Commit commits what is staged and returns the new commit's hash. If paths
+// are given, only those paths are committed and anything else staged is left
+// staged.
+func (r *Repo) Commit(message string, paths ...string) (string, error) {
+	args := []string{"commit", "-m", message}
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	if _, err := r.run(args...); err != nil {
+		return "", err
+	}
+
+	out, err := r.run("rev-parse", "--short", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Files lists the files git would include in a commit of everything: tracked
+// files and untracked ones that are not ignored.
+func (r *Repo) Files() ([]string, error) {
+	out, err := r.run("ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return splitNUL(out), nil
+}
+
+// Tracked returns the paths that git tracks.
+func (r *Repo) Tracked(paths ...string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	out, err := r.run(append([]string{"ls-files", "-z", "--"}, paths...)...)
+	if err != nil {
+		return nil, err
+	}
+	return splitNUL(out), nil
+}
+
+// Ignored returns the paths that a .gitignore or exclude file matches.
+// Tracked paths are never reported as ignored.
+func (r *Repo) Ignored(paths ...string) ([]string, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	out, err := r.run(append([]string{"check-ignore", "--"}, paths...)...)
+	if err != nil {
+		// check-ignore exits 1 when no path is ignored
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+func splitNUL(out string) []string {
+	var paths []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
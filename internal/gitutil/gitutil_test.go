@@ -0,0 +1,158 @@
+package gitutil
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// newRepo returns a repository in a temporary directory with one commit, made
+// with a git that ignores the user's and system's configuration.
+func newRepo(t *testing.T, files map[string]string) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	repo := &Repo{Dir: dir}
+	for _, args := range [][]string{{"init", "-q", "-b", "main"}, {"add", "-A"}, {"commit", "-q", "-m", "initial"}} {
+		if _, err := repo.run(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	repo := newRepo(t, map[string]string{"README.md": "# Example\n", "docs/guide.md": "Guide\n"})
+
+	if _, err := Open(filepath.Join(repo.Dir, "docs")); err != nil {
+		t.Errorf("Open(subdirectory) = %v", err)
+	}
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("Open succeeded outside a repository")
+	}
+}
+
+func TestCommitOnlyGivenPaths(t *testing.T) {
+	repo := newRepo(t, map[string]string{"README.md": "# Example\n"})
+
+	if err := repo.CreateBranch("cc/setup"); err != nil {
+		t.Fatal(err)
+	}
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "cc/setup" {
+		t.Errorf("CurrentBranch() = %q, %v", branch, err)
+	}
+
+	writeFiles(t, repo.Dir, map[string]string{"CLAUDE.md": "# Claude\n", "notes.txt": "mine\n", "README.md": "# Changed\n"})
+	if changes, err := repo.Changes(); err != nil || len(changes) != 3 {
+		t.Fatalf("Changes() = %q, %v, want 3", changes, err)
+	}
+
+	if err := repo.Add("CLAUDE.md"); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := repo.Commit("add CLAUDE.md", "CLAUDE.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" {
+		t.Error("Commit returned no hash")
+	}
+
+	// The user's own changes are left alone
+	changes, err := repo.Changes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{" M README.md", "?? notes.txt"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes() after commit = %q, want %q", changes, want)
+	}
+}
+
+func TestAbandonBranch(t *testing.T) {
+	repo := newRepo(t, map[string]string{"README.md": "# Example\n"})
+
+	if err := repo.CreateBranch("cc/setup"); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, repo.Dir, map[string]string{"CLAUDE.md": "# Claude\n"})
+	if err := repo.Add("CLAUDE.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Unstage("CLAUDE.md"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AbandonBranch("cc/setup"); err != nil {
+		t.Fatal(err)
+	}
+
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "main" {
+		t.Errorf("CurrentBranch() = %q, %v, want main", branch, err)
+	}
+	if out, err := repo.run("branch", "--list", "cc/setup"); err != nil || out != "" {
+		t.Errorf("cc/setup still exists: %q, %v", out, err)
+	}
+	// Untracked files come along rather than being lost
+	if changes, err := repo.Changes(); err != nil || !reflect.DeepEqual(changes, []string{"?? CLAUDE.md"}) {
+		t.Errorf("Changes() = %q, %v", changes, err)
+	}
+}
+
+func TestTrackedAndIgnored(t *testing.T) {
+	repo := newRepo(t, map[string]string{".gitignore": ".env\n", "config.pem": "x\n"})
+	writeFiles(t, repo.Dir, map[string]string{".env": "A=1\n", "id_rsa": "x\n"})
+
+	files := []string{".env", "config.pem", "id_rsa"}
+	tracked, err := repo.Tracked(files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"config.pem"}; !reflect.DeepEqual(tracked, want) {
+		t.Errorf("Tracked() = %q, want %q", tracked, want)
+	}
+
+	ignored, err := repo.Ignored(files...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{".env"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("Ignored() = %q, want %q", ignored, want)
+	}
+	if ignored, err := repo.Ignored("id_rsa"); err != nil || ignored != nil {
+		t.Errorf("Ignored(id_rsa) = %q, %v, want none", ignored, err)
+	}
+
+	all, err := repo.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(all)
+	if want := []string{".gitignore", "config.pem", "id_rsa"}; !reflect.DeepEqual(all, want) {
+		t.Errorf("Files() = %q, want %q", all, want)
+	}
+}
@@ -0,0 +1,219 @@
+// Package output renders command results either as human readable text or as
+// a stable JSON/YAML document for scripts.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// SchemaVersion is bumped whenever a field of Report changes incompatibly.
+const SchemaVersion = 1
+
+// Report is the machine-readable result of a command. Fields are only ever
+// added, never renamed or removed, within a schema version.
+type Report struct {
+	Version   int          `json:"version" yaml:"version"`
+	Command   string       `json:"command" yaml:"command"`
+	Status    string       `json:"status" yaml:"status"`
+	Error     string       `json:"error,omitempty" yaml:"error,omitempty"`
+	Summary   string       `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Files     []FileAction `json:"files" yaml:"files"`
+	Checks    []Check      `json:"checks,omitempty" yaml:"checks,omitempty"`
+	Runs      []Run        `json:"runs,omitempty" yaml:"runs,omitempty"`
+	Repos     []Repo       `json:"repos,omitempty" yaml:"repos,omitempty"`
+	Findings  []Finding    `json:"findings,omitempty" yaml:"findings,omitempty"`
+	Warnings  []string     `json:"warnings" yaml:"warnings"`
+	NextSteps []string     `json:"next_steps" yaml:"next_steps"`
+}
+
+// Report statuses.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// FileAction records what a command did, or would do, to a single file.
+type FileAction struct {
+	Path   string `json:"path" yaml:"path"`
+	Action string `json:"action" yaml:"action"`
+	// Diff is a unified diff of the change, included in dry runs
+	Diff string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+// Check is the outcome of a single doctor check.
+type Check struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Check statuses.
+const (
+	CheckOK   = "ok"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+// Finding is a possible secret or exposed credential file found by cc scan.
+// Line is 0 for findings about a whole file.
+type Finding struct {
+	Path    string `json:"path" yaml:"path"`
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Rule    string `json:"rule" yaml:"rule"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (f Finding) String() string {
+	location := f.Path
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.Path, f.Line)
+	}
+	return fmt.Sprintf("%s: %s (%s)", location, f.Message, f.Rule)
+}
+
+// Run summarizes a recorded cc run.
+type Run struct {
+	ID       string `json:"id" yaml:"id"`
+	Time     string `json:"time" yaml:"time"`
+	Created  int    `json:"created" yaml:"created"`
+	Replaced int    `json:"replaced" yaml:"replaced"`
+}
+
+// Repo is the result of applying cc to one repository in a fleet run.
+type Repo struct {
+	Path       string `json:"path" yaml:"path"`
+	Status     string `json:"status" yaml:"status"`
+	Created    int    `json:"created" yaml:"created"`
+	Skipped    int    `json:"skipped" yaml:"skipped"`
+	Conflicted int    `json:"conflicted" yaml:"conflicted"`
+	Branch     string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Commit     string `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func NewReport(command string) *Report {
+	return &Report{
+		Version:   SchemaVersion,
+		Command:   command,
+		Status:    StatusOK,
+		Files:     []FileAction{},
+		Warnings:  []string{},
+		NextSteps: []string{},
+	}
+}
+
+func (r *Report) AddFile(path, action string) {
+	r.Files = append(r.Files, FileAction{Path: path, Action: action})
+}
+
+func (r *Report) Warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Printer writes human logs and the final report. In text mode everything goes
+// to stdout; in json and yaml modes only the report does, and logs go to
+// stderr so stdout stays parseable.
+type Printer struct {
+	Format  string
+	Verbose bool
+	Out     io.Writer
+	Err     io.Writer
+}
+
+func NewPrinter(format string, verbose bool) (*Printer, error) {
+	switch format {
+	case FormatText, FormatJSON, FormatYAML:
+	default:
+		return nil, fmt.Errorf("invalid output format %q (must be text, json or yaml)", format)
+	}
+
+	return &Printer{Format: format, Verbose: verbose, Out: os.Stdout, Err: os.Stderr}, nil
+}
+
+func (p *Printer) Structured() bool {
+	return p.Format != FormatText
+}
+
+// Log returns the writer human-oriented progress output should go to.
+func (p *Printer) Log() io.Writer {
+	if p.Structured() {
+		return p.Err
+	}
+	return p.Out
+}
+
+func (p *Printer) Printf(format string, args ...any) {
+	fmt.Fprintf(p.Log(), format, args...)
+}
+
+func (p *Printer) Println(args ...any) {
+	fmt.Fprintln(p.Log(), args...)
+}
+
+// Emit writes the report. Text mode prints the listed files (when verbose),
+// warnings, the summary and next steps.
+func (p *Printer) Emit(r *Report) error {
+	switch p.Format {
+	case FormatJSON:
+		enc := json.NewEncoder(p.Out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatYAML:
+		enc := yaml.NewEncoder(p.Out)
+		enc.SetIndent(2)
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	if p.Verbose {
+		for _, file := range r.Files {
+			fmt.Fprintf(p.Out, "  %-9s %s\n", file.Action, file.Path)
+			if file.Diff != "" {
+				fmt.Fprint(p.Out, file.Diff)
+			}
+		}
+	}
+	if len(r.Repos) > 0 {
+		tw := tabwriter.NewWriter(p.Out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "REPOSITORY\tSTATUS\tCREATED\tSKIPPED\tCONFLICTED\tDETAILS")
+		for _, repo := range r.Repos {
+			details := repo.Error
+			if details == "" && repo.Commit != "" {
+				details = fmt.Sprintf("committed %s on %s", repo.Commit, repo.Branch)
+			} else if details == "" && repo.Branch != "" {
+				details = "branch " + repo.Branch
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%s\n",
+				repo.Path, repo.Status, repo.Created, repo.Skipped, repo.Conflicted, details)
+		}
+		tw.Flush()
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(p.Out, "⚠️  %s\n", warning)
+	}
+	if r.Summary != "" {
+		fmt.Fprintln(p.Out, r.Summary)
+	}
+	if len(r.NextSteps) > 0 {
+		fmt.Fprintln(p.Out, "\nNext steps:")
+		for i, step := range r.NextSteps {
+			fmt.Fprintf(p.Out, "%d. %s\n", i+1, step)
+		}
+	}
+
+	return nil
+}
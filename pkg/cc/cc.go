@@ -0,0 +1,715 @@
+package cc
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/onprema/cc/internal/generator"
+	"github.com/spf13/afero"
+)
+
+// Paths, relative to the project directory, where cc keeps its state.
+const (
+	BackupDir    = generator.BackupDir
+	ManifestFile = generator.ManifestFile
+)
+
+// FS is the filesystem an Engine reads from and writes to. Paths use the
+// host's separator. WriteFile must replace files atomically.
+type FS interface {
+	Stat(name string) (fs.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Mkdir(name string, perm fs.FileMode) error
+	Remove(name string) error
+}
+
+// OSFS returns the real filesystem, which Engines use by default.
+func OSFS() FS {
+	return generator.OSFS{}
+}
+
+// MemFS returns an empty in-memory filesystem, useful for previewing output
+// and for tests.
+func MemFS() FS {
+	return generator.AferoFS{Fs: afero.NewMemMapFs()}
+}
+
+// Engine plans and applies Claude Code optimization for projects. An Engine is
+// not safe for concurrent use; create one per goroutine.
+type Engine struct {
+	gen *generator.Generator
+}
+
+type Option func(*Engine)
+
+// WithFS makes the Engine use fsys instead of the real filesystem.
+func WithFS(fsys FS) Option {
+	return func(e *Engine) {
+		e.gen.FS = fsys
+	}
+}
+
+// WithLog sends human-oriented progress output to w. By default it is
+// discarded.
+func WithLog(w io.Writer) Option {
+	return func(e *Engine) {
+		e.gen.Log = w
+	}
+}
+
+// WithClock makes the Engine take the current time, used for dates in
+// generated files, from now.
+func WithClock(now func() time.Time) Option {
+	return func(e *Engine) {
+		e.gen.Now = now
+	}
+}
+
+func New(opts ...Option) *Engine {
+	gen := generator.New()
+	gen.Log = io.Discard
+
+	e := &Engine{gen: gen}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// Options describes the project to plan for.
+type Options struct {
+	// Dir is the project directory, "." if empty
+	Dir string
+	// Name is the project name used in generated files
+	Name string
+	// Type selects a registered project type; empty means a generic project
+	Type        string
+	Description string
+	// GitHub enables GitHub integration, which GitHubUsername also does.
+	GitHub bool
+	// GitHubUsername, GitHubHost and GitHubRepo locate the project on GitHub.
+	// With integration enabled, empty fields are taken from the repository's
+	// GitHub remote if it has one; the host then defaults to github.com and
+	// the repository to Name. Plan fails if GitHub is set and no owner is
+	// given or found.
+	GitHubUsername string
+	GitHubHost     string
+	GitHubRepo     string
+	// Module is the Go module path for the go project type. It defaults to
+	// the path of the git remote (host/owner/repo, followed by Dir's path
+	// within the repository), then to the GitHub repository, then to Name.
+	Module string
+	// CodeOwners are written to CODEOWNERS. Without rules, a GitHub project
+	// gets one making GitHubUsername the owner of everything.
+	CodeOwners []CodeOwnerRule
+	// IssueTemplates is IssueTemplatesForms (the default) for GitHub issue
+	// forms or IssueTemplatesMarkdown for legacy markdown templates
+	IssueTemplates string
+	// ClaudeActions adds GitHub workflows for Claude pull request review and
+	// @claude mentions, reading the API key from the ANTHROPIC_API_KEY secret
+	ClaudeActions bool
+	// Release adds a git-cliff changelog config, a tag-triggered release
+	// workflow, make release and make changelog targets and a /release
+	// command, plus a GoReleaser config for Go projects
+	Release bool
+	// AddOns are layered onto the project type, such as "docker" or "helm".
+	// AddOns lists the available ones.
+	AddOns []string
+	// Helm is shorthand for the helm add-on: a Helm chart with a values
+	// schema
+	Helm bool
+	// Dagger is shorthand for the dagger add-on: a Dagger pipeline in Go that
+	// CI runs with dagger call instead of make
+	Dagger bool
+	// DenySecrets adds rules to .claude/settings.json that stop Claude Code
+	// reading .env files and the other credential files in the project
+	DenySecrets bool
+	// CIMatrix adds a build matrix to the CI workflow. The os axis sets the
+	// runner, and go, node, python or terraform axes set the version of that
+	// stack's setup step.
+	CIMatrix []MatrixAxis
+	// Overwrite replaces existing files instead of skipping them
+	Overwrite bool
+}
+
+// CodeOwnerRule assigns the files matching Pattern, in CODEOWNERS syntax, to
+// Owners (@user, @org/team or an email address). Planning fails if Pattern
+// matches no files.
+type CodeOwnerRule struct {
+	Pattern string
+	Owners  []string
+}
+
+// MatrixAxis is one dimension of the CI build matrix.
+type MatrixAxis struct {
+	Name   string
+	Values []string
+}
+
+func (o Options) config() *generator.ProjectConfig {
+	rules := make([]generator.CodeOwnerRule, 0, len(o.CodeOwners))
+	for _, rule := range o.CodeOwners {
+		rules = append(rules, generator.CodeOwnerRule{Pattern: rule.Pattern, Owners: rule.Owners})
+	}
+
+	matrix := make([]generator.MatrixAxis, 0, len(o.CIMatrix))
+	for _, axis := range o.CIMatrix {
+		matrix = append(matrix, generator.MatrixAxis(axis))
+	}
+
+	addOns := append([]string{}, o.AddOns...)
+	if o.Helm {
+		addOns = append(addOns, "helm")
+	}
+	if o.Dagger {
+		addOns = append(addOns, "dagger")
+	}
+
+	return &generator.ProjectConfig{
+		Root:           o.Dir,
+		Name:           o.Name,
+		Type:           o.Type,
+		Description:    o.Description,
+		GitHub:         o.GitHub,
+		GitHubUsername: o.GitHubUsername,
+		GitHubHost:     o.GitHubHost,
+		GitHubRepo:     o.GitHubRepo,
+		Module:         o.Module,
+		CodeOwners:     rules,
+		IssueTemplates: o.IssueTemplates,
+		ClaudeActions:  o.ClaudeActions,
+		Release:        o.Release,
+		CIMatrix:       matrix,
+		AddOns:         addOns,
+		DenySecrets:    o.DenySecrets,
+		Overwrite:      o.Overwrite,
+		Integration:    true,
+	}
+}
+
+// Issue template styles for Options.IssueTemplates.
+const (
+	IssueTemplatesForms    = generator.IssueTemplatesForms
+	IssueTemplatesMarkdown = generator.IssueTemplatesMarkdown
+)
+
+// File actions in a Plan.
+const (
+	ActionCreated   = generator.ActionCreated
+	ActionReplaced  = generator.ActionReplaced
+	ActionMerged    = generator.ActionMerged
+	ActionSkipped   = generator.ActionSkipped
+	ActionUnchanged = generator.ActionUnchanged
+)
+
+// Plan lists the changes applying it would make.
+type Plan struct {
+	// Project is the project as planned, with defaults and values detected
+	// from the git remote filled in
+	Project Project
+	Files   []FileChange
+	// Findings are possible secrets in the planned files and in the files
+	// CLAUDE.md refers to
+	Findings []Finding
+	// CredentialFiles are the project's credential files, such as .env
+	CredentialFiles []string
+
+	plan *generator.Plan
+}
+
+// FileChange is one file in a Plan. Path is relative to the project directory
+// and slash separated. Content is the complete file as it will be written and
+// is empty for skipped files.
+type FileChange struct {
+	Path    string
+	Action  string
+	Content []byte
+}
+
+// Plan computes the files for a project without writing anything.
+func (e *Engine) Plan(ctx context.Context, opts Options) (*Plan, error) {
+	plan, err := e.gen.Plan(ctx, opts.config())
+	if err != nil {
+		return nil, err
+	}
+
+	return newPlan(plan), nil
+}
+
+// PlanAddOns computes the files for adding add-ons to a project cc already
+// generated in dir, with the options recorded when it was generated. Generated
+// files that were not modified since are updated; modified ones are skipped.
+func (e *Engine) PlanAddOns(ctx context.Context, dir string, addOns []string) (*Plan, error) {
+	plan, err := e.gen.PlanAddOns(ctx, defaultDir(dir), addOns)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPlan(plan), nil
+}
+
+func newPlan(plan *generator.Plan) *Plan {
+	files := make([]FileChange, 0, len(plan.Files))
+	for _, file := range plan.Files {
+		files = append(files, FileChange{Path: file.Path, Action: file.Action, Content: file.Content})
+	}
+
+	return &Plan{
+		Project:         project(&plan.Config),
+		Files:           files,
+		Findings:        convertFindings(plan.Findings),
+		CredentialFiles: plan.CredentialFiles,
+		plan:            plan,
+	}
+}
+
+// PlanDenySecrets computes the files for adding deny rules for the project's
+// credential files to .claude/settings.json, regenerating the project in dir
+// with its recorded options like PlanAddOns.
+func (e *Engine) PlanDenySecrets(ctx context.Context, dir string) (*Plan, error) {
+	plan, err := e.gen.PlanDenySecrets(ctx, defaultDir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	return newPlan(plan), nil
+}
+
+// Apply writes a plan made by Plan. It fails without changing anything if a
+// planned file was modified on disk in the meantime, and rolls back all of its
+// changes if a write fails.
+func (e *Engine) Apply(ctx context.Context, plan *Plan) error {
+	return e.gen.Apply(ctx, plan.plan)
+}
+
+// File is a file produced by a GeneratorFunc. Path is relative to the project
+// directory and slash separated.
+type File struct {
+	Path    string
+	Content string
+	// Merge writes Content as a cc managed block, keeping the rest of an
+	// existing file, instead of replacing it
+	Merge bool
+}
+
+// Project is what a GeneratorFunc knows about the project being planned.
+type Project struct {
+	Dir            string
+	Name           string
+	Type           string
+	Description    string
+	GitHubUsername string
+	GitHubHost     string
+	GitHubRepo     string
+	Module         string
+}
+
+func project(config *generator.ProjectConfig) Project {
+	return Project{
+		Dir:            config.Root,
+		Name:           config.Name,
+		Type:           config.Type,
+		Description:    config.Description,
+		GitHubUsername: config.GitHubUsername,
+		GitHubHost:     config.GitHubHost,
+		GitHubRepo:     config.GitHubRepo,
+		Module:         config.Module,
+	}
+}
+
+// GeneratorFunc produces files for a project. Generators run after the
+// built-in files, so they may replace any of them.
+type GeneratorFunc func(ctx context.Context, project Project) ([]File, error)
+
+// ProjectType adds files for a particular kind of project, selected with
+// Options.Type.
+type ProjectType struct {
+	Name        string
+	Description string
+	// IssueFields are added to the bug report issue form
+	IssueFields []IssueField
+	// Stacks (go, node, python, uv or terraform) are set up in the CI
+	// workflow even before their files exist
+	Stacks []string
+	// MakeTargets replace the generic Makefile targets of the same name; the
+	// others are added after them
+	MakeTargets []MakeTarget
+	// MakeVars are variable assignments (ENV ?= dev) at the top of the
+	// Makefile
+	MakeVars []string
+	// GitIgnore patterns are added to .gitignore
+	GitIgnore []string
+	// PreCommit is YAML for extra repos entries in .pre-commit-config.yaml
+	PreCommit string
+	// CIJobs is YAML for extra jobs entries in the CI workflow
+	CIJobs string
+	// Permissions are written to .claude/settings.json
+	Permissions Permissions
+	// Claude is markdown added to CLAUDE.md before Getting Started
+	Claude     string
+	Generators []GeneratorFunc
+}
+
+// Permissions are Claude Code permission rules, such as "Bash(make test)" or
+// "Bash(terraform apply:*)". Deny rules win over allow rules.
+type Permissions struct {
+	Allow []string
+	Deny  []string
+}
+
+// MakeTarget is a rule in the generated Makefile. Help is shown by make help.
+type MakeTarget struct {
+	Name   string
+	Help   string
+	Recipe []string
+}
+
+// IssueField is an extra input in the bug report issue form, such as the
+// language or tool version. Fields with Options are dropdowns.
+type IssueField struct {
+	ID          string
+	Label       string
+	Description string
+	Placeholder string
+	Options     []string
+	Required    bool
+}
+
+// RegisterProjectType makes a project type available to this Engine.
+func (e *Engine) RegisterProjectType(t ProjectType) error {
+	generators := make([]generator.FileGenerator, 0, len(t.Generators))
+	for _, fn := range t.Generators {
+		generators = append(generators, adaptGenerator(fn))
+	}
+
+	fields := make([]generator.IssueField, 0, len(t.IssueFields))
+	for _, field := range t.IssueFields {
+		fields = append(fields, generator.IssueField(field))
+	}
+
+	targets := make([]generator.MakeTarget, 0, len(t.MakeTargets))
+	for _, target := range t.MakeTargets {
+		targets = append(targets, generator.MakeTarget(target))
+	}
+
+	return e.gen.RegisterProjectType(generator.ProjectType{
+		Name:        t.Name,
+		Description: t.Description,
+		IssueFields: fields,
+		Stacks:      t.Stacks,
+		MakeTargets: targets,
+		MakeVars:    t.MakeVars,
+		GitIgnore:   t.GitIgnore,
+		PreCommit:   t.PreCommit,
+		CIJobs:      t.CIJobs,
+		Permissions: generator.Permissions(t.Permissions),
+		Claude:      t.Claude,
+		Generators:  generators,
+	})
+}
+
+// RegisterGenerator adds a generator that runs for every project this Engine
+// plans.
+func (e *Engine) RegisterGenerator(fn GeneratorFunc) {
+	e.gen.RegisterGenerator(adaptGenerator(fn))
+}
+
+// ProjectTypes lists the built-in project types.
+func ProjectTypes() []ProjectType {
+	var types []ProjectType
+	for _, t := range generator.ProjectTypes() {
+		types = append(types, ProjectType{Name: t.Name, Description: t.Description})
+	}
+	return types
+}
+
+// AddOn can be layered onto any project type with Options.AddOns.
+type AddOn struct {
+	Name        string
+	Description string
+	// Conflicts are the add-ons and project types it cannot be combined with
+	Conflicts []string
+}
+
+// AddOns lists the built-in add-ons.
+func AddOns() []AddOn {
+	var list []AddOn
+	for _, a := range generator.AddOns() {
+		list = append(list, AddOn{Name: a.Name, Description: a.Description, Conflicts: a.Conflicts})
+	}
+	return list
+}
+
+func adaptGenerator(fn GeneratorFunc) generator.FileGenerator {
+	return func(ctx context.Context, config *generator.ProjectConfig) ([]generator.File, error) {
+		files, err := fn(ctx, project(config))
+		if err != nil {
+			return nil, err
+		}
+
+		converted := make([]generator.File, 0, len(files))
+		for _, file := range files {
+			converted = append(converted, generator.File{Path: file.Path, Content: file.Content, Merge: file.Merge})
+		}
+		return converted, nil
+	}
+}
+
+// Run describes a recorded Apply. Paths are relative to the project directory.
+type Run struct {
+	ID       string
+	Time     time.Time
+	Created  []string
+	Replaced []string
+}
+
+// Runs lists the recorded runs in dir, newest first.
+func (e *Engine) Runs(ctx context.Context, dir string) ([]Run, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	runs, err := e.gen.ListRuns(defaultDir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]Run, 0, len(runs))
+	for _, run := range runs {
+		converted = append(converted, convertRun(&run))
+	}
+	return converted, nil
+}
+
+// Undo reverts the most recent run in dir.
+func (e *Engine) Undo(ctx context.Context, dir string) (*Run, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	run, err := e.gen.Undo(defaultDir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	converted := convertRun(run)
+	return &converted, nil
+}
+
+func convertRun(run *generator.Run) Run {
+	return Run{ID: run.ID, Time: run.Time, Created: run.Created, Replaced: run.Replaced}
+}
+
+// UninstallOptions controls Uninstall.
+type UninstallOptions struct {
+	// Force removes generated files even if they were modified
+	Force bool
+	// DryRun reports what would be removed without removing it
+	DryRun bool
+}
+
+// UninstallResult lists what Uninstall did. Paths are relative to the project
+// directory.
+type UninstallResult struct {
+	Removed  []string
+	Stripped []string
+	Modified []string
+	Missing  []string
+}
+
+// Uninstall removes the files cc generated in dir.
+func (e *Engine) Uninstall(ctx context.Context, dir string, opts UninstallOptions) (*UninstallResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := e.gen.Uninstall(defaultDir(dir), opts.Force, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UninstallResult{
+		Removed:  result.Removed,
+		Stripped: result.Stripped,
+		Modified: result.Modified,
+		Missing:  result.Missing,
+	}, nil
+}
+
+// PinOptions controls PinActions.
+type PinOptions struct {
+	// DryRun reports what would be pinned without changing anything
+	DryRun bool
+}
+
+// PinResult lists what PinActions did. Paths are relative to the project
+// directory.
+type PinResult struct {
+	Files []string
+	// Pinned counts the rewritten uses: references
+	Pinned int
+	// Unpinned lists the references left alone, as "file: action@ref (reason)"
+	Unpinned []string
+}
+
+// PinActions pins the actions used by the workflows in dir's
+// .github/workflows to the commit SHAs cc ships with. Like Apply, it is
+// recorded as a run that Undo reverts.
+func (e *Engine) PinActions(ctx context.Context, dir string, opts PinOptions) (*PinResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result, err := e.gen.PinActions(defaultDir(dir), opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PinResult{Files: result.Files, Pinned: result.Pinned, Unpinned: result.Unpinned}, nil
+}
+
+// ScanIgnoreComment on a line stops the secret scanner reporting it.
+const ScanIgnoreComment = generator.ScanIgnoreComment
+
+// Finding is a possible secret. Path is relative to the project directory and
+// slash separated. Line is 0 for findings about a whole file, such as a
+// credential file CLAUDE.md refers to.
+type Finding struct {
+	Path string
+	Line int
+	// Rule names the token format, such as github-token or generic-secret
+	Rule string
+	// Secret is the matched value, masked
+	Secret string
+}
+
+// ScanOptions controls Scan.
+type ScanOptions struct {
+	// Paths are the files to scan, relative to the project directory and
+	// slash separated, such as the files git tracks. Nil scans every file.
+	Paths []string
+}
+
+// ScanResult lists what Scan found.
+type ScanResult struct {
+	Findings []Finding
+	// CredentialFiles are files such as .env or private keys, found anywhere
+	// in the project whether or not they are in Paths
+	CredentialFiles []string
+}
+
+// Scan looks for secrets in the project in dir with regular expressions for
+// common token formats and an entropy check for generic secrets.
+func (e *Engine) Scan(ctx context.Context, dir string, opts ScanOptions) (*ScanResult, error) {
+	result, err := e.gen.Scan(ctx, defaultDir(dir), opts.Paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScanResult{Findings: convertFindings(result.Findings), CredentialFiles: result.CredentialFiles}, nil
+}
+
+func convertFindings(findings []generator.Finding) []Finding {
+	converted := make([]Finding, 0, len(findings))
+	for _, finding := range findings {
+		converted = append(converted, Finding(finding))
+	}
+	return converted
+}
+
+// EnvExampleFile is the file cc generates from the environment variables a
+// project's code reads.
+const EnvExampleFile = generator.EnvExampleFile
+
+// EnvVar is an environment variable read by a project's code: Go's os.Getenv,
+// Python's os.environ and os.getenv, Node's process.env, the fields of pydantic
+// BaseSettings classes, or a Terraform variable as TF_VAR_<name>.
+type EnvVar struct {
+	Name string
+	// Default is the value the code falls back to, if it is a plain literal
+	Default string
+	// Optional is set when every use has a default
+	Optional    bool
+	Description string
+	Uses        []EnvUse
+}
+
+// EnvUse is where a variable is read. Path is relative to the project
+// directory and slash separated.
+type EnvUse struct {
+	Path string
+	Line int
+}
+
+// EnvCheckResult compares the variables a project's code reads with its
+// .env.example.
+type EnvCheckResult struct {
+	// Example is false if the project has no .env.example
+	Example bool
+	// Missing are read by the code but not in .env.example
+	Missing []EnvVar
+	// Unused are in .env.example but not read by the code
+	Unused []string
+}
+
+// CheckEnv reports the environment variables the code in dir reads that its
+// .env.example does not document, and the other way around.
+func (e *Engine) CheckEnv(ctx context.Context, dir string) (*EnvCheckResult, error) {
+	check, err := e.gen.CheckEnv(ctx, defaultDir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &EnvCheckResult{Example: check.Example, Unused: check.Unused}
+	for _, v := range check.Missing {
+		uses := make([]EnvUse, 0, len(v.Uses))
+		for _, use := range v.Uses {
+			uses = append(uses, EnvUse(use))
+		}
+		result.Missing = append(result.Missing, EnvVar{
+			Name:        v.Name,
+			Default:     v.Default,
+			Optional:    v.Optional,
+			Description: v.Description,
+			Uses:        uses,
+		})
+	}
+	return result, nil
+}
+
+// File states reported by Status.
+const (
+	StateUnchanged = generator.StateUnchanged
+	StateModified  = generator.StateModified
+	StateMissing   = generator.StateMissing
+)
+
+type FileStatus struct {
+	Path  string
+	State string
+}
+
+// Status compares every file cc generated in dir with what it wrote.
+func (e *Engine) Status(ctx context.Context, dir string) ([]FileStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	statuses, err := e.gen.CheckManifest(defaultDir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]FileStatus, 0, len(statuses))
+	for _, status := range statuses {
+		converted = append(converted, FileStatus{Path: status.Path, State: status.State})
+	}
+	return converted, nil
+}
+
+func defaultDir(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,230 @@
+package cc
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testDir = "/project"
+
+// newTestEngine returns an Engine with a fixed clock working on an in-memory
+// filesystem that holds an empty project directory.
+func newTestEngine(t *testing.T) (*Engine, FS) {
+	t.Helper()
+	fsys := MemFS()
+	if err := fsys.Mkdir(testDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	engine := New(WithFS(fsys), WithClock(func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}))
+	return engine, fsys
+}
+
+func plannedFile(t *testing.T, plan *Plan, path string) FileChange {
+	t.Helper()
+	for _, file := range plan.Files {
+		if file.Path == path {
+			return file
+		}
+	}
+	t.Fatalf("plan has no %s", path)
+	return FileChange{}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	ctx := context.Background()
+	engine, fsys := newTestEngine(t)
+
+	plan, err := engine.Plan(ctx, Options{Dir: testDir, Name: "example", Description: "An example project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Project.Name != "example" || plan.Project.Module != "example" {
+		t.Errorf("project = %+v", plan.Project)
+	}
+	claude := plannedFile(t, plan, "CLAUDE.md")
+	if claude.Action != ActionCreated || !strings.Contains(string(claude.Content), "An example project") {
+		t.Errorf("CLAUDE.md = %s %q", claude.Action, claude.Content)
+	}
+
+	// Planning writes nothing
+	if _, err := fsys.Stat(filepath.Join(testDir, "CLAUDE.md")); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("CLAUDE.md exists before Apply: %v", err)
+	}
+
+	if err := engine.Apply(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range plan.Files {
+		data, err := fsys.ReadFile(filepath.Join(testDir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != string(file.Content) {
+			t.Errorf("%s differs from the plan", file.Path)
+		}
+	}
+
+	runs, err := engine.Runs(ctx, testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 {
+		t.Errorf("got %d runs, want 1", len(runs))
+	}
+
+	plan, err = engine.Plan(ctx, Options{Dir: testDir, Name: "example", Description: "An example project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range plan.Files {
+		if file.Action != ActionUnchanged {
+			t.Errorf("second plan %s = %s, want %s", file.Path, file.Action, ActionUnchanged)
+		}
+	}
+}
+
+func TestApplyFailsOnChangedFile(t *testing.T) {
+	ctx := context.Background()
+	engine, fsys := newTestEngine(t)
+
+	plan, err := engine.Plan(ctx, Options{Dir: testDir, Name: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.WriteFile(filepath.Join(testDir, "CLAUDE.md"), []byte("# Mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.Apply(ctx, plan); err == nil {
+		t.Fatal("Apply succeeded over a file written after planning")
+	}
+	if _, err := fsys.Stat(filepath.Join(testDir, "Makefile")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Makefile written by a failed Apply: %v", err)
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	engine, fsys := newTestEngine(t)
+	plan, err := engine.Plan(context.Background(), Options{Dir: testDir, Name: "example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Apply(context.Background(), plan); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := map[string]func() error{
+		"Runs":       func() error { _, err := engine.Runs(ctx, testDir); return err },
+		"Undo":       func() error { _, err := engine.Undo(ctx, testDir); return err },
+		"Uninstall":  func() error { _, err := engine.Uninstall(ctx, testDir, UninstallOptions{}); return err },
+		"PinActions": func() error { _, err := engine.PinActions(ctx, testDir, PinOptions{}); return err },
+		"Scan":       func() error { _, err := engine.Scan(ctx, testDir, ScanOptions{}); return err },
+		"CheckEnv":   func() error { _, err := engine.CheckEnv(ctx, testDir); return err },
+		"Status":     func() error { _, err := engine.Status(ctx, testDir); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s() = %v, want %v", name, err, context.Canceled)
+		}
+	}
+
+	// Nothing was undone or removed
+	if _, err := fsys.Stat(filepath.Join(testDir, "CLAUDE.md")); err != nil {
+		t.Errorf("CLAUDE.md after cancelled calls: %v", err)
+	}
+}
+
+func TestRegisterProjectType(t *testing.T) {
+	ctx := context.Background()
+	engine, _ := newTestEngine(t)
+
+	widget := ProjectType{
+		Name:        "widget",
+		Description: "A widget",
+		MakeTargets: []MakeTarget{{Name: "polish", Help: "Polish the widget", Recipe: []string{"widget polish"}}},
+		GitIgnore:   []string{"*.widget"},
+		Permissions: Permissions{Allow: []string{"Bash(widget polish)"}, Deny: []string{"Bash(widget ship:*)"}},
+		Claude:      "## Widgets\n\nPolish before shipping.\n",
+		Generators: []GeneratorFunc{func(ctx context.Context, project Project) ([]File, error) {
+			return []File{{Path: "widget.toml", Content: "name = \"" + project.Name + "\"\n"}}, nil
+		}},
+	}
+	if err := engine.RegisterProjectType(widget); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.RegisterProjectType(widget); err == nil {
+		t.Error("registering widget twice succeeded")
+	}
+
+	plan, err := engine.Plan(ctx, Options{Dir: testDir, Name: "gizmo", Type: "widget"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]string{
+		"widget.toml":           "name = \"gizmo\"\n",
+		"Makefile":              "widget polish",
+		".gitignore":            "*.widget",
+		".claude/settings.json": "Bash(widget ship:*)",
+		"CLAUDE.md":             "Polish before shipping.",
+	} {
+		if content := string(plannedFile(t, plan, path).Content); !strings.Contains(content, want) {
+			t.Errorf("%s has no %q:\n%s", path, want, content)
+		}
+	}
+
+	other, _ := newTestEngine(t)
+	if _, err := other.Plan(ctx, Options{Dir: testDir, Name: "gizmo", Type: "widget"}); err == nil || !strings.Contains(err.Error(), "widget") {
+		t.Errorf("another Engine's Plan error = %v, want an unknown widget type", err)
+	}
+}
+
+func TestRegisterGenerator(t *testing.T) {
+	ctx := context.Background()
+	engine, fsys := newTestEngine(t)
+
+	if err := fsys.WriteFile(filepath.Join(testDir, "NOTES.md"), []byte("# Notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got Project
+	engine.RegisterGenerator(func(ctx context.Context, project Project) ([]File, error) {
+		got = project
+		return []File{
+			{Path: "NOTES.md", Content: "Generated for " + project.Name + "\n", Merge: true},
+			{Path: "CLAUDE.md", Content: "# Replaced\n"},
+		}, nil
+	})
+
+	plan, err := engine.Plan(ctx, Options{Dir: testDir, Name: "example", GitHubUsername: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Dir != testDir || got.Name != "example" || got.GitHubRepo != "example" || got.Module != "github.com/acme/example" {
+		t.Errorf("generator got %+v", got)
+	}
+
+	notes := plannedFile(t, plan, "NOTES.md")
+	if notes.Action != ActionMerged || !strings.HasPrefix(string(notes.Content), "# Notes\n") || !strings.Contains(string(notes.Content), "Generated for example") {
+		t.Errorf("NOTES.md = %s %q", notes.Action, notes.Content)
+	}
+	// Generators run after the built-in files and win
+	if claude := plannedFile(t, plan, "CLAUDE.md"); string(claude.Content) != "# Replaced\n" {
+		t.Errorf("CLAUDE.md = %q", claude.Content)
+	}
+
+	engine.RegisterGenerator(func(ctx context.Context, project Project) ([]File, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := engine.Plan(ctx, Options{Dir: testDir, Name: "example"}); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Plan error = %v, want the generator's", err)
+	}
+}
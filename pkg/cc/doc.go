@@ -0,0 +1,32 @@
+// Package cc is the public Go API of cc, for embedding the Claude Code project
+// generator in other tools.
+//
+// An Engine computes a Plan for a project, listing every file it would create,
+// replace, merge or skip, and then applies it:
+//
+//	engine := cc.New()
+//	plan, err := engine.Plan(ctx, cc.Options{Name: "service", Description: "..."})
+//	if err != nil {
+//		return err
+//	}
+//	for _, file := range plan.Files {
+//		fmt.Println(file.Action, file.Path)
+//	}
+//	return engine.Apply(ctx, plan)
+//
+// Apply is atomic: if any write fails, every change made so far is rolled back.
+// Each applied plan is recorded so it can later be reverted with Engine.Undo.
+//
+// Engines read and write through an FS, so projects can be generated into
+// memory or any other storage by passing WithFS. Custom project types and file
+// generators are added with Engine.RegisterProjectType and
+// Engine.RegisterGenerator.
+//
+// # Compatibility
+//
+// This package follows semantic versioning. Within a major version, exported
+// identifiers are not removed or renamed, function signatures do not change,
+// and fields are only added to structs. Code should therefore use keyed struct
+// literals. New file actions, file states and options may be added in minor
+// releases. Everything under internal/ is exempt and may change at any time.
+package cc